@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// botSearchLimit bounds the frontier search (cells examined per step)
+const botSearchLimit = 10000
+
+// bot explores without a human at the keyboard
+type bot struct {
+	x, y     int
+	strategy string // "random" or "frontier"
+	rng      *rand.Rand
+	visited  map[[2]int]bool
+}
+
+// runBot takes steps moves using the given strategy, pausing delay between them
+// Example: runBot(0, 0, "frontier", 50, time.Second, 1) maps the nearest 50 unseen cells
+func runBot(x, y int, strategy string, steps int, delay time.Duration, seed int64) error {
+	if strategy != "random" && strategy != "frontier" {
+		return fmt.Errorf("unknown bot strategy %q (use random or frontier)", strategy)
+	}
+	b := &bot{
+		x:        x,
+		y:        y,
+		strategy: strategy,
+		rng:      rand.New(rand.NewSource(seed)),
+		visited:  map[[2]int]bool{{x, y}: true},
+	}
+
+	// Don't re-explore what earlier sessions already found
+	if m, err := fetchMap(context.Background(), tuiMapRadius); err == nil {
+		for _, c := range m.Cells {
+			b.visited[[2]int{c.X, c.Y}] = true
+		}
+	}
+
+	failures := 0
+	for i := 0; i < steps; i++ {
+		direction := b.next()
+		fromX, fromY := b.x, b.y
+		reply, _ := runCommand(&b.x, &b.y, []string{"move", direction}) // Position comes from the Coordinator
+		fmt.Printf("[bot %d/%d] move %s: %s\n", i+1, steps, direction, reply)
+
+		if b.x == fromX && b.y == fromY {
+			failures++                               // Refused or unreachable; we're where we were
+			runCommand(&b.x, &b.y, []string{"flee"}) // In case something's attacking
+			runCommand(&b.x, &b.y, []string{"rest"}) // In case it was for want of stamina
+		}
+		b.visited[[2]int{b.x, b.y}] = true
+		time.Sleep(delay)
+	}
+	fmt.Printf("[bot] done: %d moves, %d failed, %d cells known, now at (%d,%d)\n",
+		steps, failures, len(b.visited), b.x, b.y)
+	return nil
+}
+
+// next picks the direction of the bot's next move
+func (b *bot) next() string {
+	if b.strategy == "frontier" {
+		if d, ok := b.towardFrontier(); ok {
+			return d
+		}
+	}
+	return directions[b.rng.Intn(len(directions))].name
+}
+
+// towardFrontier finds the closest unvisited cell (breadth-first) and
+// returns the first step of the path there
+// Example: At (0,0) with (0,1) unseen -> "north"
+func (b *bot) towardFrontier() (string, bool) {
+	type node struct {
+		pos   [2]int
+		first string // Direction of the first step from the start
+	}
+	start := [2]int{b.x, b.y}
+	seen := map[[2]int]bool{start: true}
+	queue := []node{{pos: start}}
+
+	for len(queue) > 0 && len(seen) < botSearchLimit {
+		n := queue[0]
+		queue = queue[1:]
+		// Shuffle so equally close frontiers aren't always explored north-first
+		for _, i := range b.rng.Perm(len(directions)) {
+			d := directions[i]
+			next := [2]int{n.pos[0] + d.dx, n.pos[1] + d.dy}
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			first := n.first
+			if first == "" {
+				first = d.name
+			}
+			if !b.visited[next] {
+				return first, true
+			}
+			queue = append(queue, node{pos: next, first: first})
+		}
+	}
+	return "", false
+}
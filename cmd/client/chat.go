@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+// Reconnect backoff for the chat stream, doubling up to the max
+const (
+	chatRetryMin = time.Second
+	chatRetryMax = 30 * time.Second
+)
+
+// chatCommand sends what follows the command word to one of the chat scopes
+// send is a method expression, since coord isn't set yet when commands are built
+func chatCommand(scope string, send func(*coordclient.Client, context.Context, string) (*coordclient.Reply, error)) func(x, y *int, args []string) (string, bool) {
+	return func(x, y *int, args []string) (string, bool) {
+		if len(args) == 0 {
+			return fmt.Sprintf("%s what? Use: %s <message>", strings.ToUpper(scope[:1])+scope[1:], scope), false
+		}
+		ctx, span := tracer.Start(context.Background(), scope)
+		defer span.End()
+		reply, err := send(coord, ctx, strings.Join(args, " "))
+		if err != nil {
+			return "Nobody hears you: " + describeError(err), false
+		}
+		return reply.Message, false
+	}
+}
+
+// formatChat is how someone else's message reads at the prompt
+// Example: {shout, "bob", (3,4), "over here"} -> "bob shouts from (3,4): over here"
+func formatChat(m coordclient.ChatMessage) string {
+	switch m.Scope {
+	case "say":
+		return fmt.Sprintf("%s says: %s", m.From, m.Text)
+	case "shout":
+		return fmt.Sprintf("%s shouts from (%d,%d): %s", m.From, m.X, m.Y, m.Text)
+	default:
+		return fmt.Sprintf("[global] %s: %s", m.From, m.Text)
+	}
+}
+
+// listenChat shows chat as it arrives until ctx ends, reconnecting when the
+// stream drops (e.g. the Coordinator replica we were on restarted)
+func listenChat(ctx context.Context, show func(string)) {
+	wait := chatRetryMin
+	for ctx.Err() == nil {
+		start := time.Now()
+		err := coord.Events(ctx, func(m coordclient.ChatMessage) {
+			show(formatChat(m))
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > chatRetryMax {
+			wait = chatRetryMin // It was up a good while; this is a fresh problem
+		}
+		if err != nil && wait == chatRetryMin {
+			show("(Chat disconnected: " + describeError(err) + "; reconnecting)")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(2*wait, chatRetryMax)
+	}
+}
@@ -0,0 +1,704 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+// maxMoveSteps caps "move <direction> <steps>" so a typo can't walk forever
+const maxMoveSteps = 20
+
+// directions maps move directions to grid steps (north is +y)
+// The Coordinator accepts any of the 8 neighbouring cells, so diagonals are one move
+var directions = []struct {
+	name   string
+	alias  string
+	dx, dy int
+}{
+	{"north", "n", 0, 1},
+	{"east", "e", 1, 0},
+	{"south", "s", 0, -1},
+	{"west", "w", -1, 0},
+	{"northeast", "ne", 1, 1},
+	{"northwest", "nw", -1, 1},
+	{"southeast", "se", 1, -1},
+	{"southwest", "sw", -1, -1},
+}
+
+// findDirection resolves a direction name or alias
+// Example: "ne" -> northeast (1,1)
+func findDirection(word string) (name string, dx, dy int, ok bool) {
+	for _, d := range directions {
+		if word == d.name || word == d.alias {
+			return d.name, d.dx, d.dy, true
+		}
+	}
+	return "", 0, 0, false
+}
+
+// command is something the player can type at the prompt or in a script
+type command struct {
+	name    string
+	aliases []string
+	usage   string
+	help    string
+	run     func(x, y *int, args []string) (string, bool) // Reply, and whether to quit
+}
+
+// commands is the full command set; filled in by init because "help" reads it
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name:  "move",
+			usage: "move <direction> [steps]",
+			help: "Walk one cell, or several in a row.\n" +
+				"Directions: north, south, east, west,\n" +
+				"northeast, northwest, southeast, southwest (or n, s, e, w, ne, nw, se, sw).\n" +
+				"A direction on its own also moves: \"ne\" is \"move northeast\", \"n 3\" is \"move north 3\".",
+			run: moveCommand,
+		},
+		{
+			name:    "look",
+			aliases: []string{"l"},
+			usage:   "look",
+			help:    "Describe the cell you're standing on.",
+			run:     lookCommand,
+		},
+		{
+			name:  "where",
+			usage: "where",
+			help:  "Ask the world where you are.",
+			run:   whereCommand,
+		},
+		{
+			name:    "time",
+			aliases: []string{"sky"},
+			usage:   "time",
+			help:    "Check the time of day and the weather.",
+			run:     timeCommand,
+		},
+		{
+			name:  "map",
+			usage: "map [radius]",
+			help:  fmt.Sprintf("Draw what you've discovered within radius cells (default %d).", defaultMapRadius),
+			run:   mapCommand,
+		},
+		{
+			name:  "enter",
+			usage: "enter [exit]",
+			help: "Go into a cave or ruins where you stand. Inside, go deeper through an exit,\n" +
+				"by number or name: enter 2, enter crystal grotto.",
+			run: enterCommand,
+		},
+		{
+			name:    "exit",
+			aliases: []string{"leave", "out"},
+			usage:   "exit",
+			help:    "Go back one room, or outside from the entrance.",
+			run:     exitCommand,
+		},
+		{
+			name:    "take",
+			aliases: []string{"get"},
+			usage:   "take <item> [count]",
+			help:    "Pick up something lying where you stand, e.g. take old coin 2.",
+			run:     takeCommand,
+		},
+		{
+			name:  "drop",
+			usage: "drop <item> [count]",
+			help:  "Put down something you carry.",
+			run:   dropCommand,
+		},
+		{
+			name:    "inventory",
+			aliases: []string{"i", "inv"},
+			usage:   "inventory",
+			help:    "List what you carry.",
+			run:     inventoryCommand,
+		},
+		{
+			name:    "vitals",
+			aliases: []string{"stats"},
+			usage:   "vitals",
+			help: "Show your health, stamina and hunger.\n" +
+				"Moving costs stamina, more in swamps and hills, less along roads;\n" +
+				"it comes back over time, slower when you're hungry.",
+			run: vitalsCommand,
+		},
+		{
+			name:    "quest",
+			aliases: []string{"quests"},
+			usage:   "quest [accept <n> | abandon <n>]",
+			help: "See what's asked of travellers here, take on quest n,\n" +
+				"or abandon quest n of your journal. Quests pay out into your inventory.",
+			run: questCommand,
+		},
+		{
+			name:    "journal",
+			aliases: []string{"j"},
+			usage:   "journal",
+			help:    "List your quests and how far along you are.",
+			run:     journalCommand,
+		},
+		{
+			name:  "rest",
+			usage: "rest",
+			help:  "Sit down to recover stamina three times as fast, until you move on.",
+			run:   restCommand,
+		},
+		{
+			name:  "eat",
+			usage: "eat <item>",
+			help:  "Eat something you carry (fish, mushroom, frog, herb or pinecone) to ease hunger.",
+			run:   eatCommand,
+		},
+		{
+			name:    "attack",
+			aliases: []string{"a", "fight"},
+			usage:   "attack",
+			help: "Strike the creature fighting you, with the best weapon you carry.\n" +
+				"Creatures may attack as you arrive somewhere, more often at night and less near a camp.\n" +
+				"Lose all your health and you wake up at home.",
+			run: fightCommand("attack"),
+		},
+		{
+			name:  "flee",
+			usage: "flee",
+			help:  "Try to get away from a fight; you can't move on until it's over.",
+			run:   fightCommand("flee"),
+		},
+		{
+			name:  "use",
+			usage: "use <item>",
+			help:  "Use something you carry in a fight: herb, fish or mushroom to heal, a torch to scare the foe off.",
+			run:   fightCommand("use"),
+		},
+		{
+			name:  "gather",
+			usage: "gather [resource]",
+			help: "Gather one of a resource where you stand, or of whatever's most plentiful.\n" +
+				"Forests give wood and resin, hills stone and iron ore, swamps reed and peat, rivers clay.\n" +
+				"What's gathered grows back over time. Each gather costs a little stamina.",
+			run: gatherCommand,
+		},
+		{
+			name:  "craft",
+			usage: "craft <item>",
+			help: "Make something from what you carry, e.g. craft rope. See recipes for what's possible.\n" +
+				"A boat crosses swollen rivers and climbing gear gets you up hills in a storm.",
+			run: craftCommand,
+		},
+		{
+			name:  "recipes",
+			usage: "recipes",
+			help:  "List what you can craft and what each takes.",
+			run:   recipesCommand,
+		},
+		{
+			name:  "build",
+			usage: "build <camp|marker|bridge|signpost|road> [text]",
+			help: "Build something where you stand, for everyone to see.\n" +
+				"Bridges go over rivers and keep them crossable in any weather;\n" +
+				"a road makes the cell easier to walk into;\n" +
+				"a signpost needs its text, e.g. build signpost Ford ahead.",
+			run: buildCommand,
+		},
+		{
+			name:  "remove",
+			usage: "remove <id> [x y]",
+			help:  "Admins only (set ADMIN_TOKEN): take down a structure here or at x,y.",
+			run:   removeCommand,
+		},
+		{
+			name:  "name",
+			usage: "name <landmark name>",
+			help:  "Name the place you stand in, e.g. name The Old Ford. Names are first come, first served.",
+			run:   nameCommand,
+		},
+		{
+			name:    "gazetteer",
+			aliases: []string{"places"},
+			usage:   "gazetteer [search]",
+			help:    "List named places, or those whose name contains search.",
+			run:     gazetteerCommand,
+		},
+		{
+			name:  "goto",
+			usage: "goto <landmark>",
+			help:  "Travel to a named place, like travel.",
+			run:   gotoCommand,
+		},
+		{
+			name:  "travel",
+			usage: "travel <x> <y>",
+			help: "Walk to a far cell by the easiest way the weather allows, a step every half second.\n" +
+				"Ctrl+C stops where you are.",
+			run: travelCommand,
+		},
+		{
+			name:  "stop",
+			usage: "stop",
+			help:  "Stop travelling, e.g. a trip started in another window.",
+			run:   stopCommand,
+		},
+		{
+			name:  "say",
+			usage: "say <message>",
+			help:  "Talk to everyone in your cell.",
+			run:   chatCommand("say", (*coordclient.Client).Say),
+		},
+		{
+			name:    "shout",
+			aliases: []string{"yell"},
+			usage:   "shout <message>",
+			help:    "Call out to everyone within a few cells.",
+			run:     chatCommand("shout", (*coordclient.Client).Shout),
+		},
+		{
+			name:    "global",
+			aliases: []string{"g"},
+			usage:   "global <message>",
+			help:    "Talk to every traveller in the world.",
+			run:     chatCommand("global", (*coordclient.Client).Global),
+		},
+		{
+			name:    "help",
+			aliases: []string{"?"},
+			usage:   "help [command]",
+			help:    "List commands, or explain one.",
+			run:     helpCommand,
+		},
+		{
+			name:    "quit",
+			aliases: []string{"q"},
+			usage:   "quit",
+			help:    "Leave DriftScape. Your position is kept for next time.",
+			run: func(x, y *int, args []string) (string, bool) {
+				return "See you next time!", true
+			},
+		},
+	}
+}
+
+// findCommand resolves a command name or alias
+// Example: "l" -> look
+func findCommand(word string) (command, bool) {
+	for _, c := range commands {
+		if word == c.name {
+			return c, true
+		}
+		for _, a := range c.aliases {
+			if word == a {
+				return c, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// runCommand carries out one command and returns what to show the player
+// Shared by the prompt, scripts and bots
+// Example: ["move", "north"] -> "You moved to a forest at (0,1)", quit=false
+func runCommand(x, y *int, words []string) (string, bool) {
+	name := strings.ToLower(words[0])
+
+	// A bare direction is a move
+	// Example: ["ne", "3"] -> ["move", "ne", "3"]
+	if _, _, _, ok := findDirection(name); ok {
+		return moveCommand(x, y, words)
+	}
+	c, ok := findCommand(name)
+	if !ok {
+		return fmt.Sprintf("Huh? %q isn't a command. Try: help", words[0]), false
+	}
+	return c.run(x, y, words[1:])
+}
+
+// moveCommand walks one or more steps, stopping at the first refused move
+// Example: ["north", "3"] -> three moves north, one reply line each
+func moveCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 || len(args) > 2 {
+		return "Where? Use: move <direction> [steps], e.g. move north 3", false
+	}
+	direction, dx, dy, ok := findDirection(strings.ToLower(args[0]))
+	if !ok {
+		return "Which way? Use: n, s, e, w, ne, nw, se, sw (or the full names)", false
+	}
+	steps := 1
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > maxMoveSteps {
+			return fmt.Sprintf("How many steps? Use a number from 1 to %d", maxMoveSteps), false
+		}
+		steps = n
+	}
+
+	ctx, span := tracer.Start(context.Background(), "move")
+	defer span.End()
+	var replies []string
+	for i := 0; i < steps; i++ {
+		reply, ok := move(ctx, x, y, dx, dy) // Updates your position and tells the Coordinator
+		replies = append(replies, reply)
+		if !ok {
+			if i > 0 {
+				replies = append(replies, fmt.Sprintf("Stopped after %d of %d steps %s.", i, steps, direction))
+			}
+			break
+		}
+	}
+	return strings.Join(replies, "\n"), false
+}
+
+func lookCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "look")
+	defer span.End()
+	return look(ctx, *x, *y), false // Shows where you are
+}
+
+// whereCommand asks the Coordinator for our position and trusts its answer
+func whereCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "where")
+	defer span.End()
+	pos, err := coord.Position(ctx)
+	if err != nil {
+		return fmt.Sprintf("You think you're at (%d,%d), but can't check: %s", *x, *y, describeError(err)), false
+	}
+	*x, *y = pos.X, pos.Y
+	if pos.Sublocation != "" {
+		kind, _, _ := strings.Cut(pos.Sublocation, "/")
+		return fmt.Sprintf("You are at (%d,%d), inside the %s (%s).", pos.X, pos.Y, kind, pos.Sublocation), false
+	}
+	return fmt.Sprintf("You are at (%d,%d).", pos.X, pos.Y), false
+}
+
+func buildCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "Build what? Use: build <camp|marker|bridge|signpost|road> [text]", false
+	}
+	ctx, span := tracer.Start(context.Background(), "build")
+	defer span.End()
+	reply, err := coord.Build(ctx, args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return "Can't build that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+// removeCommand takes a structure down by the id look shows, e.g. "signpost-1"
+func removeCommand(x, y *int, args []string) (string, bool) {
+	atX, atY := *x, *y
+	switch len(args) {
+	case 1:
+	case 3:
+		var errX, errY error
+		atX, errX = strconv.Atoi(args[1])
+		atY, errY = strconv.Atoi(args[2])
+		if errX != nil || errY != nil {
+			return "Use: remove <id> [x y], e.g. remove signpost-1 2 4", false
+		}
+	default:
+		return "Use: remove <id> [x y], e.g. remove signpost-1 2 4", false
+	}
+	ctx, span := tracer.Start(context.Background(), "remove")
+	defer span.End()
+	reply, err := coord.RemoveStructure(ctx, atX, atY, args[0])
+	if err != nil {
+		return "Can't remove that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+// timeCommand reads the world clock and weather off a look around
+func timeCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "time")
+	defer span.End()
+	reply, err := coord.Look(ctx, *x, *y)
+	if err != nil {
+		return "Can't see the sky: " + describeError(err), false
+	}
+	if reply.Weather == nil {
+		return "You can't make out the sky from here.", false
+	}
+	w := reply.Weather
+	return fmt.Sprintf("It's %s (%s). Weather: %s.", w.Clock, w.TimeOfDay, w.Kind), false
+}
+
+func enterCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "enter")
+	defer span.End()
+	reply, err := coord.Enter(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't go in: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func exitCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "exit")
+	defer span.End()
+	reply, err := coord.Exit(ctx)
+	if err != nil {
+		return "Can't go back: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func mapCommand(x, y *int, args []string) (string, bool) {
+	radius := defaultMapRadius
+	if len(args) > 0 {
+		r, err := strconv.Atoi(args[0])
+		if err != nil || r < 1 {
+			return "How far? Use: map 10", false
+		}
+		radius = r
+	}
+	ctx, span := tracer.Start(context.Background(), "map")
+	defer span.End()
+	return showMap(ctx, radius), false // Draws everything you've discovered nearby
+}
+
+// itemArgs splits "<item words> [count]"
+// Example: ["old", "coin", "2"] -> "old coin", 2; ["stick"] -> "stick", 1
+func itemArgs(args []string) (string, int, bool) {
+	count := 1
+	if len(args) > 1 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			count, args = n, args[:len(args)-1]
+		}
+	}
+	if len(args) == 0 || count < 1 {
+		return "", 0, false
+	}
+	return strings.ToLower(strings.Join(args, " ")), count, true
+}
+
+func takeCommand(x, y *int, args []string) (string, bool) {
+	name, count, ok := itemArgs(args)
+	if !ok {
+		return "Take what? Use: take <item> [count]", false
+	}
+	ctx, span := tracer.Start(context.Background(), "take")
+	defer span.End()
+	reply, err := coord.Take(ctx, name, count)
+	if err != nil {
+		return "Can't take that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func dropCommand(x, y *int, args []string) (string, bool) {
+	name, count, ok := itemArgs(args)
+	if !ok {
+		return "Drop what? Use: drop <item> [count]", false
+	}
+	ctx, span := tracer.Start(context.Background(), "drop")
+	defer span.End()
+	reply, err := coord.Drop(ctx, name, count)
+	if err != nil {
+		return "Can't drop that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func inventoryCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "inventory")
+	defer span.End()
+	reply, err := coord.Inventory(ctx)
+	if err != nil {
+		return "Can't check your pack: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func vitalsCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "vitals")
+	defer span.End()
+	reply, err := coord.Vitals(ctx)
+	if err != nil {
+		return "Can't tell how you are: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func restCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "rest")
+	defer span.End()
+	reply, err := coord.Rest(ctx)
+	if err != nil {
+		return "Can't rest: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func eatCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "Eat what? Use: eat <item>", false
+	}
+	ctx, span := tracer.Start(context.Background(), "eat")
+	defer span.End()
+	reply, err := coord.Eat(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't eat that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func gatherCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "gather")
+	defer span.End()
+	reply, err := coord.Gather(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't gather: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func craftCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "Craft what? Use: craft <item>, or see recipes", false
+	}
+	ctx, span := tracer.Start(context.Background(), "craft")
+	defer span.End()
+	reply, err := coord.Craft(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't craft that: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func recipesCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "recipes")
+	defer span.End()
+	reply, err := coord.Recipes(ctx)
+	if err != nil {
+		return "Can't list recipes: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+// fightCommand takes a turn of a fight; a defeat moves the player home
+// Example: fightCommand("use")(x, y, ["herb"]) -> "You use the herb and feel better (+8 health). ..."
+func fightCommand(action string) func(x, y *int, args []string) (string, bool) {
+	return func(x, y *int, args []string) (string, bool) {
+		if action == "use" && len(args) == 0 {
+			return "Use what? Use: use <item>", false
+		}
+		ctx, span := tracer.Start(context.Background(), action)
+		defer span.End()
+		reply, err := coord.Fight(ctx, action, strings.Join(args, " "))
+		if err != nil {
+			return fmt.Sprintf("Can't %s: %s", action, describeError(err)), false
+		}
+		*x, *y = reply.X, reply.Y
+		return reply.Message, false
+	}
+}
+
+// helpCommand lists commands, or explains one
+// Example: ["ne"] -> the help for move, since "ne" is a move
+func helpCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		var b strings.Builder
+		b.WriteString("Commands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(&b, "  %-26s %s\n", c.usage, strings.SplitN(c.help, "\n", 2)[0])
+		}
+		b.WriteString("Directions work alone too: n, s, e, w, ne, nw, se, sw. Type help <command> for more.")
+		return b.String(), false
+	}
+
+	word := strings.ToLower(args[0])
+	if _, _, _, ok := findDirection(word); ok {
+		word = "move"
+	}
+	c, ok := findCommand(word)
+	if !ok {
+		return fmt.Sprintf("No command %q. Type help for the list.", args[0]), false
+	}
+	text := "Usage: " + c.usage + "\n" + c.help
+	if len(c.aliases) > 0 {
+		text += "\nAlso: " + strings.Join(c.aliases, ", ")
+	}
+	return text, false
+}
+
+// completions lists words that could go in position index of a command line
+// Example: index 0 -> command names and directions, after "move" -> directions
+func completions(words []string, index int) []string {
+	var list []string
+	addDirections := func() {
+		for _, d := range directions {
+			list = append(list, d.name, d.alias)
+		}
+	}
+	addCommands := func() {
+		for _, c := range commands {
+			list = append(list, c.name)
+		}
+	}
+
+	switch {
+	case index == 0:
+		addCommands()
+		addDirections()
+	case index == 1 && words[0] == "move":
+		addDirections()
+	case index == 1 && words[0] == "help":
+		addCommands()
+	}
+	sort.Strings(list)
+	return list
+}
+
+// complete is the prompt's tab completion: it fills in the word before the
+// cursor as far as all matching candidates agree
+// Example: "mo" -> "move ", "move nor" -> "move north" (north, northeast and northwest all match)
+func complete(line string, pos int) (string, int, bool) {
+	before := line[:pos]
+	words := strings.Fields(before)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(before, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+
+	var matches []string
+	for _, c := range completions(words, len(words)) {
+		if strings.HasPrefix(c, strings.ToLower(partial)) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	// Longest prefix shared by every match
+	// Example: "north", "northeast", "northwest" -> "north"
+	common := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(matches) == 1 {
+		common += " "
+	}
+	if len(common) <= len(partial) {
+		return "", 0, false // Nothing to add
+	}
+
+	start := pos - len(partial)
+	newLine := line[:start] + common + line[pos:]
+	return newLine, start + len(common), true
+}
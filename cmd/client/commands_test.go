@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+// fakeCoordinator answers /move by going wherever it's asked, counting moves
+func fakeCoordinator(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var moves atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/move" {
+			http.NotFound(w, r)
+			return
+		}
+		moves.Add(1)
+		x, _ := strconv.Atoi(r.URL.Query().Get("x"))
+		y, _ := strconv.Atoi(r.URL.Query().Get("y"))
+		json.NewEncoder(w).Encode(coordclient.Reply{X: x, Y: y, Message: fmt.Sprintf("You moved to (%d,%d)", x, y)})
+	}))
+	t.Cleanup(srv.Close)
+	old := coord
+	coord = coordclient.New(srv.URL, "tester", coordclient.WithRetries(0))
+	t.Cleanup(func() { coord = old })
+	return &moves
+}
+
+func TestFindDirection(t *testing.T) {
+	tests := []struct {
+		word   string
+		name   string
+		dx, dy int
+		ok     bool
+	}{
+		{"north", "north", 0, 1, true},
+		{"n", "north", 0, 1, true},
+		{"s", "south", 0, -1, true},
+		{"e", "east", 1, 0, true},
+		{"west", "west", -1, 0, true},
+		{"ne", "northeast", 1, 1, true},
+		{"northwest", "northwest", -1, 1, true},
+		{"se", "southeast", 1, -1, true},
+		{"sw", "southwest", -1, -1, true},
+		{"up", "", 0, 0, false},
+		{"nor", "", 0, 0, false},
+		{"", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		name, dx, dy, ok := findDirection(tt.word)
+		if name != tt.name || dx != tt.dx || dy != tt.dy || ok != tt.ok {
+			t.Errorf("findDirection(%q) = %q, %d, %d, %v; want %q, %d, %d, %v",
+				tt.word, name, dx, dy, ok, tt.name, tt.dx, tt.dy, tt.ok)
+		}
+	}
+}
+
+func TestRunCommandMoves(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantMoves int32
+		wantX     int
+		wantY     int
+		wantReply string // Part of the reply
+	}{
+		{name: "move", line: "move north", wantMoves: 1, wantY: 1, wantReply: "(0,1)"},
+		{name: "bare direction", line: "e", wantMoves: 1, wantX: 1, wantReply: "(1,0)"},
+		{name: "bare diagonal alias", line: "SW", wantMoves: 1, wantX: -1, wantY: -1, wantReply: "(-1,-1)"},
+		{name: "steps", line: "move ne 3", wantMoves: 3, wantX: 3, wantY: 3, wantReply: "(3,3)"},
+		{name: "bare direction with steps", line: "s 2", wantMoves: 2, wantY: -2, wantReply: "(0,-2)"},
+		{name: "most steps", line: "move west 20", wantMoves: 20, wantX: -20, wantReply: "(-20,0)"},
+		{name: "zero steps", line: "move north 0", wantReply: "How many steps?"},
+		{name: "too many steps", line: "move north 21", wantReply: "How many steps?"},
+		{name: "steps not a number", line: "n three", wantReply: "How many steps?"},
+		{name: "no direction", line: "move", wantReply: "Where?"},
+		{name: "unknown direction", line: "move up", wantReply: "Which way?"},
+		{name: "too many words", line: "move n 2 3", wantReply: "Where?"},
+		{name: "unknown command", line: "dance", wantReply: `"dance" isn't a command`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moves := fakeCoordinator(t)
+			x, y := 0, 0
+			reply, quit := runCommand(&x, &y, strings.Fields(tt.line))
+			if quit {
+				t.Errorf("%q quit", tt.line)
+			}
+			if !strings.Contains(reply, tt.wantReply) {
+				t.Errorf("%q replied %q, want it to contain %q", tt.line, reply, tt.wantReply)
+			}
+			if got := moves.Load(); got != tt.wantMoves {
+				t.Errorf("%q made %d moves, want %d", tt.line, got, tt.wantMoves)
+			}
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("%q left the player at (%d,%d), want (%d,%d)", tt.line, x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		pos     int
+		want    string
+		wantPos int
+		wantOK  bool
+	}{
+		{name: "command", line: "mo", pos: 2, want: "move ", wantPos: 5, wantOK: true},
+		{name: "direction after move", line: "move so", pos: 7, want: "move south", wantPos: 10, wantOK: true},
+		{name: "shared prefix only", line: "move nor", pos: 8, want: "move north", wantPos: 10, wantOK: true},
+		{name: "bare direction", line: "northw", pos: 6, want: "northwest ", wantPos: 10, wantOK: true},
+		{name: "case ignored", line: "MOVE so", pos: 7, want: "MOVE south", wantPos: 10, wantOK: true},
+		{name: "help topic", line: "help mo", pos: 7, want: "help move ", wantPos: 10, wantOK: true},
+		{name: "cursor mid-line", line: "mo north", pos: 2, want: "move  north", wantPos: 5, wantOK: true},
+		{name: "nothing matches", line: "xyz", pos: 3, wantOK: false},
+		{name: "already complete", line: "move north", pos: 10, wantOK: false},
+		{name: "no completions for arguments", line: "take co", pos: 7, wantOK: false},
+		{name: "third word", line: "move north 2", pos: 12, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pos, ok := complete(tt.line, tt.pos)
+			if ok != tt.wantOK {
+				t.Fatalf("complete(%q, %d) ok = %v, want %v (got %q)", tt.line, tt.pos, ok, tt.wantOK, got)
+			}
+			if ok && (got != tt.want || pos != tt.wantPos) {
+				t.Errorf("complete(%q, %d) = %q, %d; want %q, %d", tt.line, tt.pos, got, pos, tt.want, tt.wantPos)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+func nameCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "Name this place what? Use: name <landmark name>", false
+	}
+	ctx, span := tracer.Start(context.Background(), "name")
+	defer span.End()
+	reply, err := coord.Name(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't name it: " + describeError(err), false
+	}
+	return reply.Message, false
+}
+
+func gazetteerCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "gazetteer")
+	defer span.End()
+	g, err := coord.Gazetteer(ctx, strings.Join(args, " "))
+	if err != nil {
+		return "Can't read the gazetteer: " + describeError(err), false
+	}
+	if len(g.Landmarks) == 0 {
+		return "No landmarks found.", false
+	}
+	lines := make([]string, len(g.Landmarks))
+	for i, l := range g.Landmarks {
+		lines[i] = fmt.Sprintf("%s (%d,%d), %d moves away, named by %s", l.Name, l.X, l.Y, distance(*x, *y, l.X, l.Y), l.Namer)
+	}
+	if g.Total > len(g.Landmarks) {
+		lines = append(lines, fmt.Sprintf("... and %d more; narrow your search", g.Total-len(g.Landmarks)))
+	}
+	return strings.Join(lines, "\n"), false
+}
+
+// findLandmark picks the landmark a player meant, or says why it can't
+// Example: "ford" with only "The Old Ford" named -> The Old Ford
+func findLandmark(ctx context.Context, query string) (coordclient.Landmark, string) {
+	g, err := coord.Gazetteer(ctx, query)
+	if err != nil {
+		return coordclient.Landmark{}, "Can't read the gazetteer: " + describeError(err)
+	}
+	switch {
+	case len(g.Landmarks) == 0:
+		return coordclient.Landmark{}, fmt.Sprintf("Nowhere is called %q. Try: gazetteer", query)
+	case len(g.Landmarks) == 1, strings.EqualFold(g.Landmarks[0].Name, strings.Join(strings.Fields(query), " ")):
+		return g.Landmarks[0], ""
+	}
+	names := make([]string, len(g.Landmarks))
+	for i, l := range g.Landmarks {
+		names[i] = l.Name
+	}
+	return coordclient.Landmark{}, "Which one? " + strings.Join(names, ", ")
+}
+
+// distance counts moves between two cells, diagonals being one move
+func distance(x1, y1, x2, y2 int) int {
+	return max(abs(x2-x1), abs(y2-y1))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
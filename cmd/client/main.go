@@ -1,141 +1,144 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
-	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+	"github.com/akos011221/driftscape/internal/telemetry"
+)
+
+var (
+	tracer = otel.Tracer("github.com/akos011221/driftscape/cmd/client")
+
+	// coord talks to the Coordinator as our player (positions and maps are per player)
+	coord *coordclient.Client
 )
 
 func main() {
+	tuiMode := flag.Bool("tui", false, "full-screen map with arrow-key movement")
+	scriptFile := flag.String("script", "", "run commands from this file instead of the keyboard")
+	botStrategy := flag.String("bot", "", "explore on our own: random or frontier")
+	botSteps := flag.Int("steps", 100, "moves the bot makes before stopping")
+	botDelay := flag.Duration("delay", 500*time.Millisecond, "pause between bot moves")
+	botSeed := flag.Int64("seed", time.Now().UnixNano(), "random seed for the bot")
+	player := flag.String("player", os.Getenv("PLAYER_NAME"), "player name (default \"user\", or $PLAYER_NAME)")
+	timeout := flag.Duration("timeout", coordclient.DefaultTimeout, "give up on a Coordinator request after this long")
+	retries := flag.Int("retries", coordclient.DefaultRetries, "retries for requests that fail temporarily")
+	flag.Parse()
+	if *player == "" {
+		*player = "user" // The Coordinator's default player
+	}
+
+	// Export traces if OTEL_TRACES_EXPORTER is set (e.g., "stdout")
+	flushTracing := func() {}
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), "driftscape-client")
+	if err != nil {
+		fmt.Println("Tracing disabled:", err)
+	} else {
+		flushTracing = func() { shutdownTracing(context.Background()) }
+		defer flushTracing()
+	}
+
 	coordAddr := os.Getenv("COORDINATOR_ADDR")
 	if coordAddr == "" {
 		coordAddr = "http://localhost:8080" // Default for local testing
 		fmt.Println("No COORDINATOR_ADDR set, using default:", coordAddr)
 	}
+	coord = coordclient.New(coordAddr, *player,
+		// Injects trace headers so the Coordinator joins our spans
+		coordclient.WithHTTPClient(&http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}),
+		coordclient.WithTimeout(*timeout),
+		coordclient.WithRetries(*retries),
+		coordclient.WithAdminToken(os.Getenv("ADMIN_TOKEN")), // Only needed for admin commands
+	)
 
 	// Fetch starting position from Coordinator
-	x, y, err := getStartingPosition(coordAddr)
+	pos, err := coord.Position(context.Background())
 	if err != nil {
-		fmt.Println("Failed to get starting position, defaulting to (0,0):", err)
-		x, y = 0, 0
+		fmt.Println("Failed to get starting position, defaulting to (0,0):", describeError(err))
 	}
+	x, y := pos.X, pos.Y
 
-	fmt.Println("Welcome to DriftScape!")
-	fmt.Println("Commands: move north/south/east/west, look, quit")
-
-	// A loop to keep asking for commands
-	scanner := bufio.NewScanner(os.Stdin) // Reads the keyboard input
-	for {
-		fmt.Print("> ")                // Shows a prompt to the user
-		scanner.Scan()                 // Waits for Enter hit
-		input := scanner.Text()        // Grabs what you typed as a string
-		words := strings.Fields(input) // Splits the input into words
-
-		// If you didn't type anything, skip and ask again
-		if len(words) == 0 {
-			continue
+	if *tuiMode {
+		if err := runTUI(x, y); err != nil {
+			fmt.Println("Can't start the map view:", err)
 		}
+		return
+	}
 
-		// The first word is the command (e.g., "move")
-		command := words[0]
-
-		// Decide what to do based on the command
-		switch command {
-		case "quit":
-			fmt.Println("See you next time!")
-			return
-		case "look":
-			look(coordAddr, x, y) // Shows where you are
-		case "move":
-			if len(words) < 2 { // Direction is not provided
-				fmt.Println("Where? Use: move north/south/east/west")
-				continue
-			}
-			direction := words[1]
-			move(coordAddr, &x, &y, direction) // Updates your position and tells the Coordinator
-		default:
-			fmt.Println("Huh? Try: move north, look, or quit")
+	// Headless modes exit non-zero on failure, for smoke tests and CI
+	if *scriptFile != "" || *botStrategy != "" {
+		if err := runHeadless(x, y, *scriptFile, *botStrategy, *botSteps, *botDelay, *botSeed); err != nil {
+			fmt.Println("Failed:", err)
+			flushTracing()
+			os.Exit(1)
 		}
+		return
 	}
-}
 
-// getStartingPosition asks the Coordinator the starting spot
-func getStartingPosition(coordAddr string) (int, int, error) {
-	url := fmt.Sprintf("%s/position", coordAddr)
-	resp, err := http.Get(url)
-	if err != nil {
-		return 0, 0, err
+	fmt.Println("Welcome to DriftScape! Type help for commands, Tab completes, arrows recall history.")
+	if err := runPrompt(x, y); err != nil {
+		fmt.Println("Prompt failed:", err)
 	}
-	defer resp.Body.Close()
+}
 
-	buf := make([]byte, 1024)
-	n, _ := resp.Body.Read(buf)
-	posStr := string(buf[:n])
-	x, y := parsePosition(posStr)
-	return x, y, nil
+// runHeadless runs a script file or a bot instead of the prompt
+func runHeadless(x, y int, scriptFile, strategy string, steps int, delay time.Duration, seed int64) error {
+	if scriptFile != "" {
+		f, err := os.Open(scriptFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return runScript(x, y, f)
+	}
+	return runBot(x, y, strategy, steps, delay, seed)
 }
 
 // look asks the Coordinator what's at your current spot (x,y)
-func look(coordAddr string, x, y int) {
-	// Builds a web address like "http://coordinator:8080/look?x=0y=0"
-	url := fmt.Sprintf("%s/look?x=%d&y=%d", coordAddr, x, y)
-	resp, err := http.Get(url)
+func look(ctx context.Context, x, y int) string {
+	reply, err := coord.Look(ctx, x, y)
 	if err != nil {
-		fmt.Println("Can't see anything-world's not responding!")
-		return
+		return "Can't see anything: " + describeError(err)
 	}
-	defer resp.Body.Close() // Cleans up after we're done
-
-	// Reads the Coordinator's answer (e.g., "You are in a forest")
-	buf := make([]byte, 1024)
-	n, _ := resp.Body.Read(buf)
-	fmt.Println(string(buf[:n])) // Cuts off empty space
+	return reply.Message // e.g., "You're in a forest at (0,0)"
 }
 
-// move updates your position and tells the Coordinator you moved
-func move(coordAddr string, x, y *int, direction string) {
-	newX, newY := *x, *y // Copies your current spot
-
-	// Adjust position based on direction
-	switch direction {
-	case "north":
-		newY++ // Up on the map
-	case "south":
-		newY-- // Down
-	case "east":
-		newX++ // Right
-	case "west":
-		newX-- // Left
-	default:
-		fmt.Println("Which way? Use: north, south, east, west")
-		return
-	}
-
+// move tells the Coordinator you moved one step and takes your position from its answer
+// ok is false if the move was refused or failed; the position is then unchanged
+func move(ctx context.Context, x, y *int, dx, dy int) (reply string, ok bool) {
 	// Tell the Coordinator: "I'm moving to (newX, newY)"
-	url := fmt.Sprintf("%s/move?x=%d&y=%d", coordAddr, newX, newY)
-	resp, err := http.Get(url)
+	r, err := coord.Move(ctx, *x+dx, *y+dy)
 	if err != nil {
-		fmt.Println("Can't move-world's not responding!")
-		return
+		return "Can't move: " + describeError(err), false // Position stays as it was
 	}
-	defer resp.Body.Close()
 
-	// Read the response (e.g., "You're in a plains now")
-	buf := make([]byte, 1024)
-	n, _ := resp.Body.Read(buf)
-	fmt.Println(string(buf[:n]))
-
-	// If it worked, update your position
-	*x, *y = newX, newY
+	// The Coordinator decides where we ended up
+	*x, *y = r.X, r.Y
+	return r.Message, true
 }
 
-// parsePosition converts (x,y) string to x, y int
-func parsePosition(pos string) (int, int) {
-	parts := strings.Split(pos, ",")
-	x, _ := strconv.Atoi(parts[0])
-	y, _ := strconv.Atoi(parts[1])
-	return x, y
+// describeError turns a client error into something a player can read
+// Example: 400 "Bad x!" -> "Bad x!", connection refused -> "world's not responding"
+func describeError(err error) string {
+	var se *coordclient.StatusError
+	switch {
+	case errors.As(err, &se) && se.Message != "":
+		return se.Message
+	case errors.As(err, &se):
+		return se.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "the world took too long to answer"
+	default:
+		return "world's not responding (" + err.Error() + ")"
+	}
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+const defaultMapRadius = 10 // Cells shown around you by "map"
+
+// fetchMap asks the Coordinator for discovered cells within radius of the player
+func fetchMap(ctx context.Context, radius int) (*coordclient.MapReply, error) {
+	// Center the box on where the Coordinator says we are
+	pos, err := coord.Position(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return coord.Map(ctx, pos.X-radius, pos.Y-radius, pos.X+radius, pos.Y+radius)
+}
+
+// showMap draws the discovered area as ASCII, north up
+// Example:
+//
+//	f p .
+//	s @ h      @ = you, letters = terrain, blank = undiscovered
+func showMap(ctx context.Context, radius int) string {
+	m, err := fetchMap(ctx, radius)
+	if err != nil {
+		return "Can't draw the map: " + describeError(err)
+	}
+	if len(m.Cells) == 0 {
+		return "You haven't discovered anything yet. Try look or move."
+	}
+
+	// Shrink the box to what was actually discovered (plus you)
+	minX, maxX, minY, maxY := m.X, m.X, m.Y, m.Y
+	cells := map[[2]int]string{}
+	for _, c := range m.Cells {
+		cells[[2]int{c.X, c.Y}] = c.Terrain
+		minX, maxX = min(minX, c.X), max(maxX, c.X)
+		minY, maxY = min(minY, c.Y), max(maxY, c.Y)
+	}
+
+	var b strings.Builder
+	for y := maxY; y >= minY; y-- {
+		for x := minX; x <= maxX; x++ {
+			ch := " "
+			if terrain, ok := cells[[2]int{x, y}]; ok && terrain != "" {
+				ch = terrain[:1] // "forest ..." -> "f"
+			}
+			if x == m.X && y == m.Y {
+				ch = "@"
+			}
+			b.WriteString(ch)
+			if x < maxX {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "@ = you at (%d,%d); f forest, p plains, h hill, s swamp, u unknown", m.X, m.Y)
+	return b.String()
+}
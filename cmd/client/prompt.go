@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// runPrompt reads commands until quit or end of input
+// On a terminal it offers history (up/down) and tab completion; piped input
+// is read line by line
+func runPrompt(x, y int) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return runPlainPrompt(x, y)
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return runPlainPrompt(x, y) // Can't take over the terminal; fall back
+	}
+	defer term.Restore(fd, oldState)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "> ")
+	if w, h, err := term.GetSize(fd); err == nil {
+		t.SetSize(w, h)
+	}
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return complete(line, pos)
+	}
+
+	// Chat arrives while we wait for input; the terminal redraws the prompt around it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go listenChat(ctx, func(msg string) { fmt.Fprintln(t, msg) })
+	progress = func(msg string) { fmt.Fprintln(t, msg) }
+
+	for {
+		line, err := t.ReadLine() // Ctrl+D or Ctrl+C end the session
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		// Commands run with the terminal back to normal, so Ctrl+C can stop a
+		// long one like travel instead of ending the session afterwards
+		term.Restore(fd, oldState)
+		reply, quit := runCommand(&x, &y, words)
+		if _, err := term.MakeRaw(fd); err != nil {
+			return err
+		}
+		fmt.Fprintln(t, reply) // The terminal turns \n into \r\n in raw mode
+		if quit {
+			return nil
+		}
+	}
+}
+
+// runPlainPrompt is the prompt without line editing, e.g. for piped input
+func runPlainPrompt(x, y int) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go listenChat(ctx, func(msg string) { fmt.Println(msg) })
+
+	scanner := bufio.NewScanner(os.Stdin) // Reads the keyboard input
+	for {
+		fmt.Print("> ")      // Shows a prompt to the user
+		if !scanner.Scan() { // Waits for Enter hit (or Ctrl+D)
+			return scanner.Err()
+		}
+		words := strings.Fields(scanner.Text()) // Splits the input into words
+
+		// If you didn't type anything, skip and ask again
+		if len(words) == 0 {
+			continue
+		}
+
+		reply, quit := runCommand(&x, &y, words)
+		fmt.Println(reply)
+		if quit {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// questCommand lists the quests offered here, or takes one on or gives one up
+func questCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "quest")
+	defer span.End()
+	if len(args) == 0 {
+		reply, err := coord.Quests(ctx)
+		if err != nil {
+			return "Can't ask around: " + describeError(err), false
+		}
+		return reply.Message, false
+	}
+
+	n := 0
+	if len(args) == 2 {
+		n, _ = strconv.Atoi(args[1])
+	}
+	switch {
+	case n < 1:
+		return "Use: quest, quest accept <n> or quest abandon <n>", false
+	case args[0] == "accept":
+		reply, err := coord.AcceptQuest(ctx, n)
+		if err != nil {
+			return "Can't take it on: " + describeError(err), false
+		}
+		return reply.Message, false
+	case args[0] == "abandon":
+		// Numbers are as the journal shows them; the Coordinator wants the ID
+		journal, err := coord.Journal(ctx)
+		if err != nil {
+			return "Can't read your journal: " + describeError(err), false
+		}
+		if n > len(journal.Quests) || journal.Quests[n-1].Done {
+			return fmt.Sprintf("You have no quest %d on the go. Try: journal", n), false
+		}
+		reply, err := coord.AbandonQuest(ctx, journal.Quests[n-1].ID)
+		if err != nil {
+			return "Can't abandon it: " + describeError(err), false
+		}
+		return reply.Message, false
+	}
+	return "Use: quest, quest accept <n> or quest abandon <n>", false
+}
+
+func journalCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "journal")
+	defer span.End()
+	reply, err := coord.Journal(ctx)
+	if err != nil {
+		return "Can't read your journal: " + describeError(err), false
+	}
+	return reply.Message, false
+}
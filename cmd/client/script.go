@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scriptStep is one line of a script, or a "repeat" block with its body
+type scriptStep struct {
+	line  int
+	words []string
+	count int          // Iterations, for "repeat" blocks
+	body  []scriptStep // Enclosed steps, for "repeat" blocks
+}
+
+// parseScript reads a command script. Besides normal game commands it knows:
+//
+//	# comment            ignored, as are blank lines
+//	wait 500ms           pause (any time.ParseDuration value)
+//	repeat 3 ... end     run the enclosed lines 3 times (blocks nest)
+//	expect forest        fail unless the last reply contains "forest"
+//	expect-not error     fail if the last reply contains "error"
+func parseScript(r io.Reader) ([]scriptStep, error) {
+	// open[0] collects the whole script; each "repeat" pushes a block
+	open := []*scriptStep{{}}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+			continue
+		}
+		current := open[len(open)-1]
+
+		switch words[0] {
+		case "repeat":
+			if len(words) != 2 {
+				return nil, fmt.Errorf("line %d: use: repeat <count>", line)
+			}
+			n, err := strconv.Atoi(words[1])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: bad repeat count %q", line, words[1])
+			}
+			open = append(open, &scriptStep{line: line, words: words, count: n})
+			continue
+		case "end":
+			if len(open) == 1 {
+				return nil, fmt.Errorf("line %d: end without repeat", line)
+			}
+			open = open[:len(open)-1]
+			parent := open[len(open)-1]
+			parent.body = append(parent.body, *current)
+			continue
+		case "wait":
+			if len(words) != 2 {
+				return nil, fmt.Errorf("line %d: use: wait <duration>", line)
+			}
+			if _, err := time.ParseDuration(words[1]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		case "expect", "expect-not":
+			if len(words) < 2 {
+				return nil, fmt.Errorf("line %d: use: %s <text>", line, words[0])
+			}
+		}
+		current.body = append(current.body, scriptStep{line: line, words: words})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(open) > 1 {
+		return nil, fmt.Errorf("line %d: repeat without end", open[len(open)-1].line)
+	}
+	return open[0].body, nil
+}
+
+// scriptRunner executes parsed steps against the Coordinator
+type scriptRunner struct {
+	x, y      int
+	lastReply string
+	quit      bool
+}
+
+// runScript runs a script file, stopping at the first failed expectation
+// Example: "move north" then "expect (0,1)" checks the move landed
+func runScript(x, y int, r io.Reader) error {
+	steps, err := parseScript(r)
+	if err != nil {
+		return err
+	}
+	s := &scriptRunner{x: x, y: y}
+	return s.run(steps)
+}
+
+func (s *scriptRunner) run(steps []scriptStep) error {
+	for _, step := range steps {
+		if s.quit {
+			return nil
+		}
+		args := strings.Join(step.words[1:], " ")
+		switch step.words[0] {
+		case "repeat":
+			for i := 0; i < step.count; i++ {
+				if err := s.run(step.body); err != nil {
+					return err
+				}
+			}
+		case "wait":
+			d, _ := time.ParseDuration(args) // Checked by parseScript
+			time.Sleep(d)
+		case "expect":
+			if !strings.Contains(s.lastReply, args) {
+				return fmt.Errorf("line %d: expected %q in reply %q", step.line, args, s.lastReply)
+			}
+		case "expect-not":
+			if strings.Contains(s.lastReply, args) {
+				return fmt.Errorf("line %d: did not expect %q in reply %q", step.line, args, s.lastReply)
+			}
+		default:
+			fmt.Println(">", strings.Join(step.words, " "))
+			s.lastReply, s.quit = runCommand(&s.x, &s.y, step.words)
+			fmt.Println(s.lastReply)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    []scriptStep
+		wantErr string // Part of the error, "" if none
+	}{
+		{
+			name:   "commands, comments and blanks",
+			script: "# warm up\n\nmove north\n  look  \n",
+			want: []scriptStep{
+				{line: 3, words: []string{"move", "north"}},
+				{line: 4, words: []string{"look"}},
+			},
+		},
+		{
+			name:   "wait and expect",
+			script: "wait 500ms\nexpect forest\nexpect-not bad x",
+			want: []scriptStep{
+				{line: 1, words: []string{"wait", "500ms"}},
+				{line: 2, words: []string{"expect", "forest"}},
+				{line: 3, words: []string{"expect-not", "bad", "x"}},
+			},
+		},
+		{
+			name:   "nested repeats",
+			script: "repeat 2\nmove east\nrepeat 3\nlook\nend\nend\nquit",
+			want: []scriptStep{
+				{line: 1, words: []string{"repeat", "2"}, count: 2, body: []scriptStep{
+					{line: 2, words: []string{"move", "east"}},
+					{line: 3, words: []string{"repeat", "3"}, count: 3, body: []scriptStep{
+						{line: 4, words: []string{"look"}},
+					}},
+				}},
+				{line: 7, words: []string{"quit"}},
+			},
+		},
+		{name: "empty", script: "", want: nil},
+		{name: "repeat without a count", script: "repeat\nend", wantErr: "line 1: use: repeat"},
+		{name: "negative repeat", script: "repeat -1\nend", wantErr: "line 1: bad repeat count"},
+		{name: "end without repeat", script: "look\nend", wantErr: "line 2: end without repeat"},
+		{name: "repeat without end", script: "look\nrepeat 2\nlook", wantErr: "line 2: repeat without end"},
+		{name: "bad wait", script: "wait soon", wantErr: "line 1:"},
+		{name: "wait without a time", script: "wait", wantErr: "line 1: use: wait"},
+		{name: "expect without text", script: "look\nexpect", wantErr: "line 2: use: expect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseScript(strings.NewReader(tt.script))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseScript() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+// progress shows a line while a long command is still running
+// The terminal prompt swaps in its own writer
+var progress = func(msg string) { fmt.Println(msg) }
+
+// travelCommand walks to a cell along the Coordinator's route
+func travelCommand(x, y *int, args []string) (string, bool) {
+	if len(args) != 2 {
+		return "Travel where? Use: travel <x> <y>, or goto <landmark>", false
+	}
+	toX, errX := strconv.Atoi(args[0])
+	toY, errY := strconv.Atoi(args[1])
+	if errX != nil || errY != nil {
+		return "Coordinates are whole numbers, e.g. travel 5 -3", false
+	}
+	return travel(x, y, "travel", func(ctx context.Context, handle func(coordclient.TravelEvent)) error {
+		return coord.Travel(ctx, toX, toY, handle)
+	}), false
+}
+
+// gotoCommand travels to a landmark, picked from the gazetteer
+func gotoCommand(x, y *int, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "Go where? Use: goto <landmark>", false
+	}
+	l, problem := findLandmark(context.Background(), strings.Join(args, " "))
+	if problem != "" {
+		return problem, false
+	}
+	return travel(x, y, "goto", func(ctx context.Context, handle func(coordclient.TravelEvent)) error {
+		return coord.TravelTo(ctx, l.Name, handle)
+	}), false
+}
+
+// travel runs a trip, showing each step as it's taken, until it ends or
+// Ctrl+C stops it; the position follows the player the whole way
+// Example: "(1/3) forest at (1,0), stamina 90" ... "You arrive at (3,1) after 3 steps. ..."
+func travel(x, y *int, name string, start func(context.Context, func(coordclient.TravelEvent)) error) string {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, span := tracer.Start(ctx, name)
+	defer span.End()
+
+	var result string
+	err := start(ctx, func(e coordclient.TravelEvent) {
+		*x, *y = e.X, e.Y
+		switch e.Kind {
+		case "step":
+			progress(fmt.Sprintf("(%d/%d) %s at (%d,%d), stamina %d", e.Step, e.Steps, e.Terrain, e.X, e.Y, e.Stamina))
+			if e.Message != "" {
+				progress(e.Message)
+			}
+		case "done":
+			result = e.Reply.Message
+		case "stopped":
+			result = fmt.Sprintf("%s You're at (%d,%d).", e.Reason, e.X, e.Y)
+		}
+	})
+	switch {
+	case ctx.Err() != nil:
+		// Hanging up stops the trip; the Coordinator notices before the next step
+		return fmt.Sprintf("You stop travelling at (%d,%d).", *x, *y)
+	case err != nil:
+		return "Can't travel: " + describeError(err)
+	case result == "":
+		return fmt.Sprintf("The trip was cut short at (%d,%d); travel again to carry on.", *x, *y)
+	}
+	return result
+}
+
+// stopCommand stops a trip, e.g. one left running in another window
+func stopCommand(x, y *int, args []string) (string, bool) {
+	ctx, span := tracer.Start(context.Background(), "stop")
+	defer span.End()
+	if err := coord.CancelTravel(ctx); err != nil {
+		return describeError(err), false
+	}
+	return "You'll stop at the next step.", false
+}
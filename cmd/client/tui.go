@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+const (
+	logHeight = 8  // Rows for the event log at the bottom
+	sideWidth = 40 // Columns for the description pane on the right
+	maxEvents = 100
+
+	tuiMapRadius = 50 // Discovered cells loaded at startup, around the player
+)
+
+// terrainColors paints map cells by base terrain
+// Example: "forest with a river" -> green
+var terrainColors = map[string]tcell.Color{
+	"forest":  tcell.ColorGreen,
+	"plains":  tcell.ColorYellow,
+	"hill":    tcell.ColorOlive,
+	"swamp":   tcell.ColorTeal,
+	"unknown": tcell.ColorGray,
+}
+
+// replyEvent delivers a finished /look or /move back to the UI loop
+type replyEvent struct {
+	*tcell.EventTime
+	action string // "look" or "move"
+	reply  *coordclient.Reply
+	err    error
+}
+
+// chatEvent delivers a chat message from the listener to the UI loop
+type chatEvent struct {
+	*tcell.EventTime
+	text string
+}
+
+type tui struct {
+	screen      tcell.Screen
+	x, y        int
+	visited     map[[2]int]string // (x,y) -> terrain seen there
+	description string
+	events      []string
+	busy        bool // A request is in flight; ignore movement keys
+}
+
+// runTUI shows the full-screen map until the player quits
+func runTUI(x, y int) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	defer screen.Fini()
+
+	t := &tui{
+		screen:  screen,
+		x:       x,
+		y:       y,
+		visited: map[[2]int]string{},
+	}
+	t.logEvent("Welcome to DriftScape! Arrows move, l looks, q quits.")
+
+	// Start from everything discovered in earlier sessions
+	if m, err := fetchMap(context.Background(), tuiMapRadius); err != nil {
+		t.logEvent("Couldn't load your map: " + describeError(err))
+	} else {
+		for _, c := range m.Cells {
+			t.visited[[2]int{c.X, c.Y}] = c.Terrain
+		}
+	}
+	t.request("look", x, y)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go listenChat(ctx, func(msg string) {
+		ev := &chatEvent{EventTime: &tcell.EventTime{}, text: msg}
+		ev.SetEventNow()
+		screen.PostEvent(ev)
+	})
+
+	for {
+		t.draw()
+		switch ev := screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+		case *replyEvent:
+			t.handleReply(ev)
+		case *chatEvent:
+			t.logEvent(ev.text)
+		case *tcell.EventKey:
+			if !t.handleKey(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// handleKey reacts to a key press; false means quit
+func (t *tui) handleKey(ev *tcell.EventKey) bool {
+	dx, dy := 0, 0
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return false
+	case tcell.KeyUp:
+		dy = 1 // North is up on the map
+	case tcell.KeyDown:
+		dy = -1
+	case tcell.KeyRight:
+		dx = 1
+	case tcell.KeyLeft:
+		dx = -1
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			return false
+		case 'l':
+			if !t.busy {
+				t.request("look", t.x, t.y)
+			}
+		}
+		return true
+	default:
+		return true
+	}
+
+	if t.busy {
+		t.logEvent("Still travelling...")
+		return true
+	}
+	t.request("move", t.x+dx, t.y+dy)
+	return true
+}
+
+// request calls the Coordinator in the background so the map stays responsive
+// (spawning a region pod can take a few seconds)
+func (t *tui) request(action string, x, y int) {
+	t.busy = true
+	go func() {
+		ctx, span := tracer.Start(context.Background(), action)
+		defer span.End()
+		ev := &replyEvent{EventTime: &tcell.EventTime{}, action: action}
+		ev.SetEventNow()
+		if action == "move" {
+			ev.reply, ev.err = coord.Move(ctx, x, y)
+		} else {
+			ev.reply, ev.err = coord.Look(ctx, x, y)
+		}
+		t.screen.PostEvent(ev)
+	}()
+}
+
+// handleReply records what the Coordinator said about a cell
+func (t *tui) handleReply(ev *replyEvent) {
+	t.busy = false
+	if ev.err != nil {
+		t.logEvent(fmt.Sprintf("Can't %s: %s", ev.action, describeError(ev.err)))
+		return
+	}
+	t.logEvent(ev.reply.Message)
+
+	// The position in the reply is where we actually are
+	t.x, t.y = ev.reply.X, ev.reply.Y
+	t.visited[[2]int{t.x, t.y}] = ev.reply.Terrain
+	t.description = ev.reply.Message
+}
+
+func (t *tui) logEvent(msg string) {
+	t.events = append(t.events, msg)
+	if len(t.events) > maxEvents {
+		t.events = t.events[len(t.events)-maxEvents:]
+	}
+}
+
+func (t *tui) draw() {
+	t.screen.Clear()
+	w, h := t.screen.Size()
+	mapW, mapH := w-sideWidth-1, h-logHeight-1
+	if mapW < 2 || mapH < 1 {
+		drawText(t.screen, 0, 0, w, 1, tcell.StyleDefault, "Window too small")
+		t.screen.Show()
+		return
+	}
+	t.drawMap(mapW, mapH)
+
+	// Pane borders
+	line := tcell.StyleDefault.Foreground(tcell.ColorDarkGray)
+	for row := 0; row < mapH; row++ {
+		t.screen.SetContent(mapW, row, '│', nil, line)
+	}
+	for col := 0; col < w; col++ {
+		t.screen.SetContent(col, mapH, '─', nil, line)
+	}
+
+	// Description pane
+	bold := tcell.StyleDefault.Bold(true)
+	drawText(t.screen, mapW+2, 0, sideWidth-2, 1, bold, fmt.Sprintf("Position (%d,%d)", t.x, t.y))
+	drawText(t.screen, mapW+2, 2, sideWidth-2, mapH-2, tcell.StyleDefault, t.description)
+
+	// Event log, newest at the bottom
+	start := len(t.events) - logHeight
+	if start < 0 {
+		start = 0
+	}
+	for i, msg := range t.events[start:] {
+		drawText(t.screen, 1, mapH+1+i, w-2, 1, tcell.StyleDefault, msg)
+	}
+	t.screen.Show()
+}
+
+// drawMap renders visited cells around the player, two columns per cell
+// Example: Player at (2,4) is drawn as "@" in the middle, north is up
+func (t *tui) drawMap(mapW, mapH int) {
+	cols, rows := mapW/2, mapH
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x := t.x + col - cols/2
+			y := t.y + rows/2 - row
+			terrain, seen := t.visited[[2]int{x, y}]
+			if !seen || terrain == "" {
+				continue // Fog: unvisited cells stay blank
+			}
+			base := strings.Fields(terrain)[0]
+			color, ok := terrainColors[base]
+			if !ok {
+				color = terrainColors["unknown"]
+			}
+			style := tcell.StyleDefault.Background(color).Foreground(tcell.ColorBlack)
+			ch := rune(base[0])
+			if x == t.x && y == t.y {
+				ch = '@'
+				style = style.Foreground(tcell.ColorWhite).Bold(true)
+			}
+			t.screen.SetContent(col*2, row, ch, nil, style)
+			t.screen.SetContent(col*2+1, row, ' ', nil, style)
+		}
+	}
+	if _, seen := t.visited[[2]int{t.x, t.y}]; !seen {
+		t.screen.SetContent(cols/2*2, rows/2, '@', nil, tcell.StyleDefault.Bold(true))
+	}
+}
+
+// drawText writes text into a box, wrapping on spaces
+func drawText(s tcell.Screen, x, y, w, h int, style tcell.Style, text string) {
+	row, col := 0, 0
+	for _, word := range strings.Fields(text) {
+		if col > 0 && col+1+len(word) > w {
+			row, col = row+1, 0
+		}
+		if row >= h {
+			return
+		}
+		if col > 0 {
+			col++
+		}
+		for _, r := range word {
+			if col < w {
+				s.SetContent(x+col, y+row, r, nil, style)
+			}
+			col++
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// chatChannel is the Redis pub/sub channel every replica publishes to
+	// and relays from, so players on different replicas hear each other
+	chatChannel = "chat"
+
+	shoutRadius   = 5   // Cells a shout carries, diagonals included
+	maxChatLength = 200 // Characters per message
+
+	// A player may send chatBurst messages per chatWindow
+	chatBurst  = 5
+	chatWindow = 10 * time.Second
+
+	// streamKeepalive keeps idle event streams from being cut by proxies
+	streamKeepalive = 15 * time.Second
+)
+
+var (
+	chatMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_chat_messages_total",
+		Help: "Chat messages sent by players on this replica, by scope.",
+	}, []string{"scope"})
+	chatDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_chat_deliveries_total",
+		Help: "Chat messages pushed to event streams on this replica, by result.",
+	}, []string{"result"})
+	eventStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "driftscape_event_streams",
+		Help: "Clients connected to /events on this replica.",
+	})
+)
+
+// chatMessage is what's published on chatChannel and sent to listeners
+type chatMessage struct {
+	Scope string    `json:"scope"` // "say", "shout" or "global"
+	From  string    `json:"from"`
+	Text  string    `json:"text"`
+	X     int       `json:"x"` // Where the speaker stood
+	Y     int       `json:"y"`
+	Time  time.Time `json:"time"`
+}
+
+// hears reports whether a listener at (x,y) is in range of m
+// Example: A shout from (2,4) reaches (6,0) but not (8,4)
+func (m chatMessage) hears(x, y int) bool {
+	switch m.Scope {
+	case "say":
+		return x == m.X && y == m.Y
+	case "shout":
+		return max(abs(x-m.X), abs(y-m.Y)) <= shoutRadius
+	default:
+		return true
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// listener is one open /events stream on this replica
+type listener struct {
+	player string
+	ch     chan chatMessage
+}
+
+var (
+	listenersMu sync.Mutex
+	listeners   = map[*listener]struct{}{}
+
+	// closingStreams is closed when the server shuts down, ending every
+	// /events stream so the drain doesn't wait on them
+	closingStreams = make(chan struct{})
+)
+
+// chatRateScript counts a message in KEYS[1], starting a window of ARGV[1] ms
+// if there's none, in one step so a count can't be left without an expiry
+var chatRateScript = redis.NewScript(`
+local sent = redis.call("INCR", KEYS[1])
+if redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return sent
+`)
+
+func chatHandler(scope string) http.HandlerFunc {
+	// Publish a message for everyone in range, on any replica
+	// Example: "/say?player=alice&text=hello" -> players at alice's cell hear "hello"
+	return func(w http.ResponseWriter, r *http.Request) {
+		player, err := getPlayer(r)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		text, err := chatText(r.URL.Query().Get("text"))
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+
+		// Fixed window per player: the first message starts it
+		// Example: "alice:chat-rate" -> 6 within 10s -> refused
+		rateKey := player + ":chat-rate"
+		sent, err := chatRateScript.Run(r.Context(), rdb, []string{rateKey}, chatWindow.Milliseconds()).Int64()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Counting chat messages failed", "err", err)
+			http.Error(w, "Redis error", 500)
+			return
+		}
+		if sent > chatBurst {
+			http.Error(w, "You're talking too fast; take a breath.", 429)
+			return
+		}
+
+		pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+		if err != nil && err != redis.Nil {
+			slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+			http.Error(w, "Redis error", 500)
+			return
+		}
+		x, y := parsePosition(pos) // New players are at 0,0
+
+		m := chatMessage{Scope: scope, From: player, Text: text, X: x, Y: y, Time: time.Now().UTC()}
+		data, err := json.Marshal(m)
+		if err != nil {
+			http.Error(w, "Encoding failed", 500)
+			return
+		}
+		if err := rdb.Publish(r.Context(), chatChannel, data).Err(); err != nil {
+			slog.ErrorContext(r.Context(), "Publishing chat failed", "err", err)
+			http.Error(w, "Redis error", 500)
+			return
+		}
+		chatMessages.WithLabelValues(scope).Inc()
+
+		var msg string
+		switch scope {
+		case "say":
+			msg = fmt.Sprintf("You say: %s", text)
+		case "shout":
+			msg = fmt.Sprintf("You shout: %s", text)
+		default:
+			msg = fmt.Sprintf("[global] You: %s", text)
+		}
+		writeReply(w, r, reply{X: x, Y: y, Terrain: storedTerrain(r.Context(), x, y), Message: msg})
+	}
+}
+
+func chatText(text string) (string, error) {
+	// Tidy a message and check it's sendable
+	// Example: "  hi\tthere " -> "hi there"; "" -> error
+	text = strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+	switch {
+	case text == "":
+		return "", fmt.Errorf("Say what?")
+	case len([]rune(text)) > maxChatLength:
+		return "", fmt.Errorf("That's too long; keep it under %d characters.", maxChatLength)
+	}
+	return text, nil
+}
+
+// runChatRelay passes messages from chatChannel to this replica's listeners
+// Every replica runs it; each only delivers to its own /events streams
+func runChatRelay(ctx context.Context) {
+	sub := rdb.Subscribe(ctx, chatChannel)
+	defer sub.Close()
+	for msg := range sub.Channel() { // Closed when ctx ends; reconnects by itself
+		var m chatMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable chat message", "err", err)
+			continue
+		}
+		deliverChat(ctx, m)
+	}
+}
+
+func deliverChat(ctx context.Context, m chatMessage) {
+	// Send m to every local listener in range, except the speaker
+	// Example: alice says "hi" at (2,4) -> bob, streaming here at (2,4), gets it
+	listenersMu.Lock()
+	var targets []*listener
+	for l := range listeners {
+		if l.player != m.From {
+			targets = append(targets, l)
+		}
+	}
+	listenersMu.Unlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	// Listeners move, so check where they are now
+	var positions []any
+	if m.Scope != "global" {
+		keys := make([]string, len(targets))
+		for i, l := range targets {
+			keys[i] = positionKey(l.player)
+		}
+		var err error
+		positions, err = rdb.MGet(ctx, keys...).Result()
+		if err != nil {
+			slog.WarnContext(ctx, "Reading listener positions failed", "err", err)
+			chatDelivered.WithLabelValues("error").Add(float64(len(targets)))
+			return
+		}
+	}
+
+	for i, l := range targets {
+		if positions != nil {
+			pos, _ := positions[i].(string)
+			if !m.hears(parsePosition(pos)) {
+				continue
+			}
+		}
+		select {
+		case l.ch <- m:
+			chatDelivered.WithLabelValues("ok").Inc()
+		default:
+			chatDelivered.WithLabelValues("dropped").Inc() // Slow reader; don't hold up everyone else
+		}
+	}
+}
+
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	// Stream chat to the Client as Server-Sent Events until it disconnects
+	// Example: "event: chat\ndata: {"scope":"say","from":"bob","text":"hi",...}"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	rc := http.NewResponseController(w)
+
+	l := &listener{player: player, ch: make(chan chatMessage, 16)}
+	listenersMu.Lock()
+	listeners[l] = struct{}{}
+	listenersMu.Unlock()
+	eventStreams.Inc()
+	defer func() {
+		listenersMu.Lock()
+		delete(listeners, l)
+		listenersMu.Unlock()
+		eventStreams.Dec()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(r.Context(), "Streaming unsupported", "err", err)
+		return
+	}
+
+	// An open stream keeps the player present; closing it lets them fade out
+	// Example: alice quits at (2,4) -> bob's next look no longer lists her
+	here := func() (int, int) {
+		pos, _ := rdb.Get(r.Context(), positionKey(player)).Result()
+		return parsePosition(pos)
+	}
+	x, y := here()
+	touchPresence(r.Context(), player, x, y)
+	defer func() {
+		x, y := here()
+		leavePresence(context.WithoutCancel(r.Context()), player, x, y)
+	}()
+
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-closingStreams:
+			return // Client reconnects to another replica
+		case <-keepalive.C:
+			x, y := here()
+			touchPresence(r.Context(), player, x, y)
+			fmt.Fprint(w, ": keepalive\n\n")
+		case m := <-l.ch:
+			data, _ := json.Marshal(m)
+			fmt.Fprintf(w, "event: chat\ndata: %s\n\n", data)
+		}
+		if err := rc.Flush(); err != nil {
+			return // Client went away
+		}
+	}
+}
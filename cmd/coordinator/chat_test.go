@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChatText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", text: "hello", want: "hello"},
+		{name: "spaces squeezed", text: "  hi\tthere \n", want: "hi there"},
+		{name: "control characters dropped", text: "ding\x07dong\x1b[2J", want: "ding dong [2J"},
+		{name: "unicode kept", text: "héllo wörld", want: "héllo wörld"},
+		{name: "empty", text: "", wantErr: true},
+		{name: "only whitespace", text: " \t\n ", wantErr: true},
+		{name: "at the limit", text: strings.Repeat("é", maxChatLength), want: strings.Repeat("é", maxChatLength)},
+		{name: "too long", text: strings.Repeat("a", maxChatLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chatText(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("chatText(%q) error = %v, want error %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("chatText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
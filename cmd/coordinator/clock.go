@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// dayLength is how long a world day lasts in real time (DAY_LENGTH)
+// Example: 24m -> one world minute passes every real second
+var dayLength = 24 * time.Minute
+
+var worldClock = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "driftscape_world_clock_minutes",
+	Help: "World minutes since the world began, as last advanced by the leader.",
+})
+
+// runWorldClock advances the world clock one world minute at a time
+// Only the leader runs it, so time doesn't pass twice as fast with two replicas
+// Example: DAY_LENGTH=24m ticks every second; with no leader the world stands still
+func runWorldClock(ctx context.Context) {
+	ticker := time.NewTicker(dayLength / world.MinutesPerDay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now, err := world.AdvanceClock(ctx, rdb, 1)
+		if err != nil {
+			slog.WarnContext(ctx, "Advancing world clock failed", "err", err)
+			continue
+		}
+		worldClock.Set(float64(now))
+		if now%60 == 0 {
+			slog.DebugContext(ctx, "World clock", "time", now.String(), "phase", now.Phase())
+		}
+	}
+}
+
+// sky is the weather part of look and move replies
+type sky struct {
+	Kind      string `json:"kind"`        // e.g. "heavy rain"
+	TimeOfDay string `json:"time_of_day"` // e.g. "dusk"
+	Clock     string `json:"clock"`       // e.g. "day 3, 18:40"
+}
+
+func skyOf(w *pb.Weather) *sky {
+	// Regions that couldn't read the clock send no weather
+	if w == nil {
+		return nil
+	}
+	return &sky{Kind: w.Kind, TimeOfDay: w.TimeOfDay, Clock: w.Clock}
+}
+
+func seeWeather(w *pb.Weather) string {
+	// Appended right after the terrain in look and move messages
+	// Example: {summary: "under heavy rain at dusk"} -> ", under heavy rain at dusk"
+	if w == nil || w.Summary == "" {
+		return ""
+	}
+	return ", " + w.Summary
+}
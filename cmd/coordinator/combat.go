@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/items"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// homeX, homeY is where players wake up after losing a fight (HOME_POINT)
+var homeX, homeY int
+
+// fightView is a fight as the Client sees it
+type fightView struct {
+	Foe          string `json:"foe"`
+	FoeHealth    int    `json:"foe_health"`
+	FoeMaxHealth int    `json:"foe_max_health"`
+	Outcome      string `json:"outcome,omitempty"` // "won", "fled", "scared off" or "defeated" once it's over
+}
+
+func viewFight(f *pb.Fight) *fightView {
+	if f == nil || f.Foe == "" {
+		return nil
+	}
+	return &fightView{Foe: f.Foe, FoeHealth: int(f.FoeHealth), FoeMaxHealth: int(f.FoeMaxHealth), Outcome: f.Outcome}
+}
+
+func fightKey(player string) string {
+	// Redis hash of the cell a player is fighting in and the turns played;
+	// the region keeps the fight itself
+	// Example: "alice:fight" -> {"at": "2,4", "turn": "3"}
+	return player + ":fight"
+}
+
+func fighting(ctx context.Context, player string) (string, error) {
+	// Where the player is fighting, or "" if they aren't
+	pos, err := rdb.HGet(ctx, fightKey(player), "at").Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return pos, err
+}
+
+func seeFight(f *pb.Fight) string {
+	// Sentence appended to move messages when something attacks
+	// Example: ". A grey wolf attacks! Attack, flee or use an item"
+	if f == nil || f.Foe == "" {
+		return ""
+	}
+	return ". " + f.Log + " Attack, flee or use an item"
+}
+
+func encounter(ctx context.Context, player string, x, y int) *pb.Fight {
+	// Ask the region whether something attacks the player on arrival
+	// A region that doesn't answer lets them arrive in peace
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var f *pb.Fight
+	err := callRegion(ctx, podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		f, err = client.Encounter(ctx, &pb.EncounterRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Player: player})
+		return err
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Rolling encounter failed", "region", podName, "err", err)
+		return nil
+	}
+	if f.Foe == "" {
+		return nil
+	}
+	if err := rdb.HSet(ctx, fightKey(player), "at", fmt.Sprintf("%d,%d", x, y), "turn", f.Turn).Err(); err != nil {
+		slog.ErrorContext(ctx, "Saving fight failed", "player", player, "err", err)
+	}
+	return f
+}
+
+// fightReply is what /fight tells the Client
+type fightReply struct {
+	X         int           `json:"x"` // Where the player is; home after a defeat
+	Y         int           `json:"y"`
+	Fight     *fightView    `json:"fight,omitempty"`
+	Vitals    *vitalSigns   `json:"vitals"`
+	Inventory []items.Stack `json:"inventory,omitempty"` // After using an item
+	Message   string        `json:"message"`
+}
+
+func fightHandler(w http.ResponseWriter, r *http.Request) {
+	// Take a turn in the player's fight: attack, flee or use an item
+	// Example: "?player=alice&action=attack" -> "You hit the grey wolf for 5. The grey wolf strikes you for 3."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	action := strings.ToLower(r.URL.Query().Get("action"))
+	item := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("item")))
+	current, err := rdb.HGetAll(r.Context(), fightKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading fight failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	pos := current["at"]
+	if pos == "" {
+		http.Error(w, "There's nothing to fight.", 400)
+		return
+	}
+	x, y := parsePosition(pos)
+	turn, _ := strconv.Atoi(current["turn"])
+
+	v, err := readVitals(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading vitals failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	// Only what's carried can be used: it's taken up front and given back
+	// if the region refuses the turn, so it can't be spent twice; a region
+	// that doesn't answer may have played it, so that keeps the item
+	// Example: "use herb" with one herb -> none left unless the region refuses
+	if action == "use" {
+		ok, err := items.Take(r.Context(), rdb, inventoryKey(player), item, 1)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Taking from inventory failed", "err", err)
+			http.Error(w, "Redis error", 500)
+			return
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("You don't have any %s.", item), 400)
+			return
+		}
+	}
+
+	// The region owning the cell plays the turn, so replicas can't double up on it
+	// The best weapon carried is swung, e.g. a rusty sword over a stick
+	carried := readInventory(r.Context(), player)
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	req := &pb.FightRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Player: player, Action: action, Item: item,
+		Weapon: items.BestWeapon(carried), Health: int32(v.Health), Turn: int32(turn)}
+	var res *pb.Fight
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		res, err = client.FightTurn(ctx, req)
+		return err
+	})
+	if _, refused := regionRefusal(err); action == "use" && (refused || err == nil && !res.ItemUsed) {
+		if err := rdb.HIncrBy(r.Context(), inventoryKey(player), item, 1).Err(); err != nil {
+			slog.ErrorContext(r.Context(), "Returning item failed", "player", player, "item", item, "err", err)
+		}
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		rdb.Del(r.Context(), fightKey(player)) // The region forgot it, e.g. it was restarted
+		http.Error(w, "There's nothing to fight here anymore.", 400)
+		return
+	case codes.FailedPrecondition:
+		// Another request played this turn first, e.g. from a second window
+		http.Error(w, "That turn was already played; try again.", 409)
+		return
+	}
+	if msg, ok := regionRefusal(err); ok {
+		http.Error(w, msg, 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region refused fight turn", "region", podName, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+
+	defeated := res.Outcome == "defeated"
+	v, _, err = updateVitals(r.Context(), player, func(v *vitals) string {
+		v.Health = min(maxHealth, v.Health+float64(res.Healed)-float64(res.Damage))
+		v.Resting = false
+		if defeated {
+			v.Health, v.Stamina = maxHealth, maxStamina/2
+		}
+		return ""
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Updating vitals failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if res.Outcome != "" {
+		err = rdb.Del(r.Context(), fightKey(player)).Err()
+	} else {
+		err = rdb.HSet(r.Context(), fightKey(player), "turn", res.Turn).Err()
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Saving fight failed", "player", player, "err", err)
+	}
+
+	msg := res.Log
+	if defeated {
+		if merr := respawn(r.Context(), player, pos); merr != nil {
+			http.Error(w, merr.msg, merr.code)
+			return
+		}
+		x, y = homeX, homeY
+		msg += fmt.Sprintf(" You wake up at (%d,%d), sore but alive.", x, y)
+	}
+	if res.Outcome == "" || defeated {
+		msg += " " + v.String()
+	}
+	rep := fightReply{X: x, Y: y, Fight: viewFight(res), Vitals: v.signs(), Message: msg}
+	if res.ItemUsed {
+		rep.Inventory = readInventory(r.Context(), player)
+	}
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
+
+func respawn(ctx context.Context, player, oldPos string) *moveError {
+	// Carry a beaten player home, starting its region if nobody's there
+	// Example: alice falls at (7,-2) -> wakes up at (0,0)
+	slog.InfoContext(ctx, "Player defeated", "player", player, "at", oldPos)
+	if err := rdb.Del(ctx, sublocationKey(player)).Err(); err != nil {
+		slog.WarnContext(ctx, "Clearing sublocation failed", "player", player, "err", err)
+	}
+	err := rdb.Get(ctx, fmt.Sprintf("region:%d,%d", homeX, homeY)).Err()
+	if err == redis.Nil || !regionExists(ctx, homeX, homeY) {
+		spawnRegion(ctx, homeX, homeY)
+	} else if err != nil {
+		slog.ErrorContext(ctx, "Reading region failed", "x", homeX, "y", homeY, "err", err)
+		return &moveError{500, "Redis error"}
+	}
+	return relocate(ctx, player, oldPos, homeX, homeY)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/akos011221/driftscape/internal/items"
+)
+
+// recipe is what it takes to make an item
+type recipe struct {
+	Item  string        `json:"item"`
+	Needs []items.Stack `json:"needs"` // All used up
+	Makes int           `json:"makes"`
+	Use   string        `json:"use"` // What it's good for, for the recipe list
+}
+
+// recipes are everything players can craft, listed in this order
+// Materials come from gathering (wood, resin, stone, iron ore, reed, peat, clay) or off the ground
+var recipes = []recipe{
+	{"rope", []items.Stack{{Name: "reed", Count: 3}}, 1, "ties things together"},
+	{"torch", []items.Stack{{Name: "stick", Count: 1}, {Name: "resin", Count: 1}}, 1, "scares creatures off in a fight"},
+	{"stone axe", []items.Stack{{Name: "wood", Count: 1}, {Name: "stone", Count: 2}, {Name: "rope", Count: 1}}, 1, "hits harder than a stick"},
+	{"clay pot", []items.Stack{{Name: "clay", Count: 3}, {Name: "peat", Count: 1}}, 1, "a sturdy pot, fired over peat"},
+	{"boat", []items.Stack{{Name: "wood", Count: 6}, {Name: "rope", Count: 2}, {Name: "resin", Count: 2}}, 1, "crosses rivers even in flood"},
+	{"climbing gear", []items.Stack{{Name: "rope", Count: 3}, {Name: "iron ore", Count: 2}}, 1, "climbs hills even in a storm"},
+}
+
+// tools get their carrier past terrain the weather would close, by hazard
+// (see world.BlockedPast), with what it reads like when they do
+var tools = []struct {
+	item   string
+	hazard string
+	use    string
+}{
+	{"boat", "river", "You row across the swollen river"},
+	{"climbing gear", "hill", "Your climbing gear gets you up the hill"},
+}
+
+var crafted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "driftscape_items_crafted_total",
+	Help: "Items crafted by players, by item.",
+}, []string{"item"})
+
+func findRecipe(item string) (recipe, bool) {
+	for _, rc := range recipes {
+		if rc.Item == item {
+			return rc, true
+		}
+	}
+	return recipe{}, false
+}
+
+func (rc recipe) String() string {
+	// Example: "boat: wood (6), rope (2), resin (2); crosses rivers even in flood"
+	return fmt.Sprintf("%s: %s; %s", rc.Item, items.Describe(rc.Needs), rc.Use)
+}
+
+func hazardsPast(carried []items.Stack) map[string]bool {
+	// Which hazards the tools a player carries get them past
+	// Example: [boat 1] [stick 2] -> {"river": true}
+	past := map[string]bool{}
+	for _, s := range carried {
+		for _, t := range tools {
+			if s.Name == t.item {
+				past[t.hazard] = true
+			}
+		}
+	}
+	return past
+}
+
+func seeTools(terrain string, past map[string]bool) string {
+	// Sentence appended to a move the weather would have stopped without tools
+	// Example: "plains with a river", past river -> ". You row across the swollen river"
+	var used []string
+	for _, t := range tools {
+		if past[t.hazard] && strings.Contains(terrain, t.hazard) {
+			used = append(used, t.use)
+		}
+	}
+	if len(used) == 0 {
+		return ""
+	}
+	return ". " + strings.Join(used, ". ")
+}
+
+// craftReply is what /craft and /recipes tell the Client
+type craftReply struct {
+	Message   string        `json:"message"`
+	Recipes   []recipe      `json:"recipes,omitempty"`   // /recipes only
+	Inventory []items.Stack `json:"inventory,omitempty"` // After crafting
+}
+
+func writeCraftReply(w http.ResponseWriter, r *http.Request, rep craftReply) {
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
+
+func recipesHandler(w http.ResponseWriter, r *http.Request) {
+	// List what can be crafted and from what
+	// Example: "?player=alice" -> "Recipes: rope: reed (3); ties things together, ..."
+	lines := make([]string, len(recipes))
+	for i, rc := range recipes {
+		lines[i] = rc.String()
+	}
+	writeCraftReply(w, r, craftReply{Message: "Recipes:\n" + strings.Join(lines, "\n"), Recipes: recipes})
+}
+
+func craftHandler(w http.ResponseWriter, r *http.Request) {
+	// Turn materials in the inventory into an item
+	// Example: "?player=alice&item=rope" -> reed (3) becomes rope
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("item")))
+	rc, ok := findRecipe(name)
+	if !ok {
+		if !items.ValidName(name) {
+			http.Error(w, "Craft what?", 400)
+			return
+		}
+		http.Error(w, fmt.Sprintf("You don't know how to make %s; check the recipes.", name), 400)
+		return
+	}
+
+	ok, err = items.Exchange(r.Context(), rdb, inventoryKey(player), rc.Needs, items.Stack{Name: rc.Item, Count: rc.Makes})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Crafting failed", "player", player, "item", rc.Item, "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("You need %s to make %s.", items.Describe(rc.Needs), rc.Item), 400)
+		return
+	}
+	crafted.WithLabelValues(rc.Item).Inc()
+	slog.InfoContext(r.Context(), "Crafted", "player", player, "item", rc.Item)
+	writeCraftReply(w, r, craftReply{
+		Message:   fmt.Sprintf("You make %s from %s.", items.Describe([]items.Stack{{Name: rc.Item, Count: rc.Makes}}), items.Describe(rc.Needs)),
+		Inventory: readInventory(r.Context(), player),
+	})
+}
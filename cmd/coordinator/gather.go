@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/items"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// gatherStamina is what one gather takes out of a player
+const gatherStamina = 5.0
+
+// material is a resource growing where the player stands, as the Client sees it
+type material struct {
+	Name   string `json:"name"`
+	Amount int    `json:"amount"` // Left to gather
+	Max    int    `json:"max"`    // What it grows back to
+}
+
+func resourcesOf(list []*pb.Resource) []material {
+	// Convert a region's resources for a reply
+	var out []material
+	for _, r := range list {
+		out = append(out, material{Name: r.Name, Amount: int(r.Amount), Max: int(r.Max)})
+	}
+	return out
+}
+
+func seeResources(list []material) string {
+	// Sentence appended to look and move messages
+	// Example: [wood 4/6] [resin 0/3] -> ". To gather: wood (4/6), resin (0/3)"
+	if len(list) == 0 {
+		return ""
+	}
+	parts := make([]string, len(list))
+	for i, r := range list {
+		parts[i] = fmt.Sprintf("%s (%d/%d)", r.Name, r.Amount, r.Max)
+	}
+	return ". To gather: " + strings.Join(parts, ", ")
+}
+
+func gatherHandler(w http.ResponseWriter, r *http.Request) {
+	// Gather one of a resource where the player stands into their inventory
+	// Example: "?player=alice&resource=wood" -> "You gather wood. To gather: wood (3/6), resin (3/3)."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("resource")))
+	if name != "" && !items.ValidName(name) {
+		http.Error(w, "Gather what?", 400)
+		return
+	}
+	sub, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if sub != "" {
+		http.Error(w, "There's nothing to gather in here; exit first!", 400)
+		return
+	}
+	if at, err := fighting(r.Context(), player); err != nil {
+		slog.ErrorContext(r.Context(), "Reading fight failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	} else if at != "" {
+		http.Error(w, "You're in a fight! Attack, flee or use an item.", 400)
+		return
+	}
+	if v, err := readVitals(r.Context(), player); err != nil {
+		slog.ErrorContext(r.Context(), "Reading vitals failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	} else if v.Stamina < gatherStamina {
+		http.Error(w, "You're too exhausted to gather anything. Rest or eat first.", 400)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	// The region owns what grows on it; it refuses when it's run out
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var got *pb.Gathered
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		got, err = client.Gather(ctx, &pb.GatherRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Resource: name})
+		return err
+	})
+	if msg, ok := regionRefusal(err); ok {
+		http.Error(w, msg, 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region refused gather", "region", podName, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+
+	// Already gathered, so it's paid for even if stamina ran low meanwhile
+	tired := ""
+	v, _, err := updateVitals(r.Context(), player, func(v *vitals) string {
+		v.Stamina = max(0, v.Stamina-gatherStamina)
+		v.Resting = false
+		return ""
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Updating vitals failed", "err", err)
+	} else {
+		tired = seeVitals(v)
+	}
+	if err := rdb.HIncrBy(r.Context(), inventoryKey(player), got.Resource, 1).Err(); err != nil {
+		// Leave it on the ground rather than let it vanish
+		slog.ErrorContext(r.Context(), "Saving inventory failed, dropping resource", "player", player, "item", got.Resource, "err", err)
+		callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+			_, err := client.DropItem(ctx, &pb.ItemRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Name: got.Resource, Count: 1})
+			return err
+		})
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	notes := advanceQuests(r.Context(), player, questEvent{X: x, Y: y, Item: got.Resource, Count: 1})
+	writeItemReply(w, r, itemReply{
+		Message:   "You gather " + got.Resource + seeResources(resourcesOf(got.Resources)) + tired + seeQuests(notes) + ".",
+		Inventory: readInventory(r.Context(), player),
+	})
+}
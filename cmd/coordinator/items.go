@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/items"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// itemReply is what /take, /drop and /inventory tell the Client
+// Sent as JSON when the request accepts it, otherwise as Message text
+type itemReply struct {
+	Message   string        `json:"message"`
+	Inventory []items.Stack `json:"inventory"`
+	Ground    []items.Stack `json:"ground,omitempty"` // What's left here, after a take or drop
+}
+
+func inventoryKey(player string) string {
+	// Redis hash of what a player carries
+	// Example: "alice:inventory" -> {"stick": 2}
+	return player + ":inventory"
+}
+
+func stacks(list []*pb.Item) []items.Stack {
+	// Convert a region's item list for replies
+	// Example: [{stick 2}] -> [{Name: "stick", Count: 2}]
+	out := make([]items.Stack, 0, len(list))
+	for _, it := range list {
+		out = append(out, items.Stack{Name: it.Name, Count: int(it.Count)})
+	}
+	return out
+}
+
+func seeItems(ground []items.Stack) string {
+	// Sentence appended to look and move messages
+	// Example: [stick 2] -> ". You see: stick (2)"
+	if len(ground) == 0 {
+		return ""
+	}
+	return ". You see: " + items.Describe(ground)
+}
+
+func getItem(r *http.Request) (string, int, error) {
+	// Parse which item and how many
+	// Example: "?item=old+coin&count=2" -> "old coin", 2; no count -> 1
+	name := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("item")))
+	if !items.ValidName(name) {
+		return "", 0, fmt.Errorf("Bad item name!")
+	}
+	count, err := queryInt(r, "count", 1)
+	if err != nil || count < 1 || count > items.MaxCount {
+		return "", 0, fmt.Errorf("Count must be 1 to %d!", items.MaxCount)
+	}
+	return name, count, nil
+}
+
+func playerRegion(ctx context.Context, player string) (int, int, error) {
+	// Find the player's cell and make sure its pod is up to act on it
+	// Example: "alice" at "2,4" -> 2, 4 (spawning region-2-4 if it was reaped)
+	x, y := 0, 0
+	pos, err := rdb.Get(ctx, positionKey(player)).Result()
+	if err != nil && err != redis.Nil {
+		return 0, 0, err
+	}
+	if err == nil {
+		x, y = parsePosition(pos)
+	}
+	if !regionExists(ctx, x, y) {
+		spawnRegion(ctx, x, y)
+	}
+	return x, y, nil
+}
+
+func readInventory(ctx context.Context, player string) []items.Stack {
+	// A failed read only leaves the inventory out of the reply
+	hash, err := rdb.HGetAll(ctx, inventoryKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading inventory failed", "player", player, "err", err)
+	}
+	return items.Stacks(hash)
+}
+
+func takeHandler(w http.ResponseWriter, r *http.Request) {
+	// Move items from the ground where the player stands into their inventory
+	// Example: "?player=alice&item=stick" -> "You pick up stick."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name, count, err := getItem(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	// The region owns its ground; it refuses if there isn't enough
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	req := &pb.ItemRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Name: name, Count: int32(count)}
+	var left *pb.Items
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		left, err = client.TakeItem(ctx, req)
+		return err
+	})
+	if status.Code(err) == codes.NotFound {
+		http.Error(w, fmt.Sprintf("There isn't %s here.", items.Describe([]items.Stack{{Name: name, Count: count}})), 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region refused take", "region", podName, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+
+	if err := rdb.HIncrBy(r.Context(), inventoryKey(player), name, int64(count)).Err(); err != nil {
+		// Put it back rather than let it vanish
+		slog.ErrorContext(r.Context(), "Saving inventory failed, returning item", "player", player, "item", name, "err", err)
+		callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+			_, err := client.DropItem(ctx, req)
+			return err
+		})
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	notes := advanceQuests(r.Context(), player, questEvent{X: x, Y: y, Item: name, Count: count})
+	writeItemReply(w, r, itemReply{
+		Message:   fmt.Sprintf("You pick up %s", items.Describe([]items.Stack{{Name: name, Count: count}})) + seeQuests(notes) + ".",
+		Inventory: readInventory(r.Context(), player),
+		Ground:    stacks(left.Items),
+	})
+}
+
+func dropHandler(w http.ResponseWriter, r *http.Request) {
+	// Move items from the player's inventory onto the ground where they stand
+	// Example: "?player=alice&item=stick&count=2" -> "You drop stick (2)."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name, count, err := getItem(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	what := items.Describe([]items.Stack{{Name: name, Count: count}})
+	ok, err := items.Take(r.Context(), rdb, inventoryKey(player), name, count)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Updating inventory failed", "player", player, "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("You don't have %s.", what), 400)
+		return
+	}
+
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	req := &pb.ItemRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Name: name, Count: int32(count)}
+	var left *pb.Items
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		left, err = client.DropItem(ctx, req)
+		return err
+	})
+	if err != nil {
+		// Give it back rather than let it vanish
+		slog.WarnContext(r.Context(), "Region refused drop, returning item", "region", podName, "err", err)
+		if err := rdb.HIncrBy(r.Context(), inventoryKey(player), name, int64(count)).Err(); err != nil {
+			slog.ErrorContext(r.Context(), "Returning item failed", "player", player, "item", name, "err", err)
+		}
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+
+	writeItemReply(w, r, itemReply{
+		Message:   fmt.Sprintf("You drop %s.", what),
+		Inventory: readInventory(r.Context(), player),
+		Ground:    stacks(left.Items),
+	})
+}
+
+func inventoryHandler(w http.ResponseWriter, r *http.Request) {
+	// List what the player carries
+	// Example: "?player=alice" -> "You carry: flint, stick (2)"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	hash, err := rdb.HGetAll(r.Context(), inventoryKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading inventory failed", "player", player, "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	carried := items.Stacks(hash)
+	msg := "You aren't carrying anything."
+	if len(carried) > 0 {
+		msg = "You carry: " + items.Describe(carried)
+	}
+	writeItemReply(w, r, itemReply{Message: msg, Inventory: carried})
+}
+
+func writeItemReply(w http.ResponseWriter, r *http.Request, rep itemReply) {
+	// Example: text "You drop stick." or {"message":...,"inventory":[...]}
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// landmarksKey is the Redis hash of every landmark, by normalized name
+// Example: "landmarks" -> {"the old ford": {"name": "The Old Ford", "x": 14, "y": -3, ...}}
+const landmarksKey = "landmarks"
+
+// maxGazetteer caps how many landmarks one search returns
+const maxGazetteer = 20
+
+// landmarkPattern allows names like "The Old Ford" or "Miller's Rest"
+var landmarkPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z' -]{2,31}$`)
+
+// blockedWords can't start a word of a landmark name, which catches "shitty"
+// and the like; letters spelled out one by one count as a word ("f u c k")
+var blockedWords = []string{"fuck", "shit", "cunt", "twat", "wank", "bitch", "asshole", "bastard", "whore", "slut"}
+
+// blockedExact are only refused as whole words, so "Peacock Hill" stays fine
+var blockedExact = []string{"cock", "dick", "piss", "tits", "arse"}
+
+// landmark is a named cell
+type landmark struct {
+	Name    string `json:"name"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Namer   string `json:"namer"`
+	NamedAt int64  `json:"named_at"` // Unix seconds
+}
+
+func landmarkCellKey(x, y int) string {
+	// Redis key holding a cell's landmark name, if it has one
+	// Example: "landmark:14,-3" -> "The Old Ford"
+	return fmt.Sprintf("landmark:%d,%d", x, y)
+}
+
+func normalizeName(name string) string {
+	// Names are unique regardless of case and spacing
+	// Example: "  the OLD   ford" -> "the old ford"
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func checkLandmarkName(name string) (string, error) {
+	// Tidy a proposed name and check it's allowed
+	// Example: "the old  ford" -> "the old ford"; "x" -> error
+	name = strings.Join(strings.Fields(name), " ")
+	if !landmarkPattern.MatchString(name) {
+		return "", fmt.Errorf("Landmark names are 3 to 32 letters, spaces, hyphens or apostrophes, starting with a letter.")
+	}
+	// Check each word, and runs of single letters as one word ("f u c k")
+	words := strings.FieldsFunc(normalizeName(name), func(r rune) bool { return r == ' ' || r == '-' || r == '\'' })
+	var spelled, letters string
+	for _, word := range words {
+		if len(word) == 1 {
+			letters += word
+			continue
+		}
+		spelled += " " + letters
+		letters = ""
+	}
+	words = append(words, strings.Fields(spelled+" "+letters)...)
+	for _, word := range words {
+		for _, bad := range blockedWords {
+			if strings.HasPrefix(word, bad) {
+				return "", fmt.Errorf("That name isn't allowed; try another.")
+			}
+		}
+		for _, bad := range blockedExact {
+			if word == bad || word == bad+"s" {
+				return "", fmt.Errorf("That name isn't allowed; try another.")
+			}
+		}
+	}
+	return name, nil
+}
+
+// nameScript names a cell unless the name or the cell is taken
+// KEYS[1] is landmarksKey, KEYS[2] the cell's key; ARGV is the normalized
+// name, the landmark JSON and the display name
+// Returns 1 on success, -1 if the name is taken, -2 if the cell is named
+var nameScript = redis.NewScript(`
+if redis.call("HEXISTS", KEYS[1], ARGV[1]) == 1 then
+	return -1
+end
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	return -2
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("SET", KEYS[2], ARGV[3])
+return 1
+`)
+
+func cellLandmark(ctx context.Context, x, y int) string {
+	// The name of the cell at (x,y), or "" if it has none
+	name, err := rdb.Get(ctx, landmarkCellKey(x, y)).Result()
+	if err != nil && err != redis.Nil {
+		slog.WarnContext(ctx, "Reading landmark failed", "x", x, "y", y, "err", err)
+	}
+	return name
+}
+
+func seeLandmark(name string) string {
+	// Sentence appended to look and move messages
+	// Example: "The Old Ford" -> ". This place is known as The Old Ford"
+	if name == "" {
+		return ""
+	}
+	return ". This place is known as " + name
+}
+
+func nameHandler(w http.ResponseWriter, r *http.Request) {
+	// Name the cell the player stands in, first come first served
+	// Example: "?player=alice&name=The Old Ford" at (14,-3) -> landmark "The Old Ford"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name, err := checkLandmarkName(r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if err != nil && err != redis.Nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	x, y := parsePosition(pos)
+
+	data, err := json.Marshal(landmark{Name: name, X: x, Y: y, Namer: player, NamedAt: time.Now().Unix()})
+	if err != nil {
+		http.Error(w, "Encoding failed", 500)
+		return
+	}
+	res, err := nameScript.Run(r.Context(), rdb, []string{landmarksKey, landmarkCellKey(x, y)},
+		normalizeName(name), data, name).Int()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Naming landmark failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	switch res {
+	case -1:
+		http.Error(w, fmt.Sprintf("Somewhere is already called %s.", name), 409)
+		return
+	case -2:
+		http.Error(w, fmt.Sprintf("This place already has a name: %s.", cellLandmark(r.Context(), x, y)), 409)
+		return
+	}
+	slog.InfoContext(r.Context(), "Landmark named", "name", name, "x", x, "y", y)
+	writeReply(w, r, reply{X: x, Y: y, Terrain: storedTerrain(r.Context(), x, y), Landmark: name,
+		Message: fmt.Sprintf("From now on, (%d,%d) is known as %s.", x, y, name)})
+}
+
+// searchLandmarks finds landmarks whose name contains q, best matches first:
+// exact, then prefix, then anywhere, each alphabetical
+// Example: "ford" -> [Fordham, The Old Ford]
+func searchLandmarks(ctx context.Context, q string) ([]landmark, error) {
+	q = normalizeName(q)
+	all, err := rdb.HGetAll(ctx, landmarksKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	rank := func(norm string) int {
+		switch {
+		case norm == q:
+			return 0
+		case strings.HasPrefix(norm, q):
+			return 1
+		default:
+			return 2
+		}
+	}
+	var found []landmark
+	for norm, data := range all {
+		if !strings.Contains(norm, q) {
+			continue
+		}
+		var l landmark
+		if err := json.Unmarshal([]byte(data), &l); err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable landmark", "name", norm, "err", err)
+			continue
+		}
+		found = append(found, l)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		a, b := normalizeName(found[i].Name), normalizeName(found[j].Name)
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		return a < b
+	})
+	return found, nil
+}
+
+func gazetteerHandler(w http.ResponseWriter, r *http.Request) {
+	// Search the world's landmarks by name; no query lists them all
+	// Example: "?q=ford" -> text "The Old Ford (14,-3), named by alice"
+	found, err := searchLandmarks(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading landmarks failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	total := len(found)
+	if total > maxGazetteer {
+		found = found[:maxGazetteer]
+	}
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"landmarks": found, "total": total})
+		return
+	}
+	if total == 0 {
+		fmt.Fprint(w, "No landmarks found.")
+		return
+	}
+	lines := make([]string, len(found))
+	for i, l := range found {
+		lines[i] = fmt.Sprintf("%s (%d,%d), named by %s", l.Name, l.X, l.Y, l.Namer)
+	}
+	if total > len(found) {
+		lines = append(lines, "... and "+strconv.Itoa(total-len(found))+" more; narrow your search")
+	}
+	fmt.Fprint(w, strings.Join(lines, "\n"))
+}
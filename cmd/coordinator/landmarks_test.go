@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestCheckLandmarkName(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", in: "Old Ford", want: "Old Ford"},
+		{name: "spaces squeezed", in: "  the old   ford ", want: "the old ford"},
+		{name: "hyphen and apostrophe", in: "Giant's Stair-Well", want: "Giant's Stair-Well"},
+		{name: "too short", in: "ab", wantErr: true},
+		{name: "too long", in: "abcdefghijklmnopqrstuvwxyzabcdefg", wantErr: true},
+		{name: "starts with a hyphen", in: "-ford", wantErr: true},
+		{name: "digits", in: "Ford 2", wantErr: true},
+		{name: "accented letters", in: "Café Hill", wantErr: true},
+		{name: "blocked word", in: "Shit Creek", wantErr: true},
+		{name: "blocked word as a prefix", in: "Shitty Creek", wantErr: true},
+		{name: "blocked word in any case", in: "FUCK hill", wantErr: true},
+		{name: "spelled out", in: "f u c k", wantErr: true},
+		{name: "spelled out with hyphens", in: "s-h-i-t hill", wantErr: true},
+		{name: "exact word", in: "Cock Rock", wantErr: true},
+		{name: "exact word plural", in: "Tits Peak", wantErr: true},
+		{name: "exact word inside another", in: "Peacock Hill", want: "Peacock Hill"},
+		{name: "exact word as a prefix", in: "Dickens Row", want: "Dickens Row"},
+		{name: "innocent single letters", in: "a b c hill", want: "a b c hill"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkLandmarkName(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkLandmarkName(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkLandmarkName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
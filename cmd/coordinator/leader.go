@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/akos011221/driftscape/internal/cluster"
+)
+
+// leaseName is the Lease object replicas compete for
+const leaseName = "driftscape-coordinator"
+
+var isLeader = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "driftscape_coordinator_is_leader",
+	Help: "1 if this replica holds the coordinator Lease and runs background work.",
+})
+
+// runLeaderElection runs background work only while this replica holds the Lease
+// Example: Of three coordinator pods, only the leader reaps orphaned regions,
+// respawns missing ones and keeps the world clock; if it dies, another takes
+// over within the lease duration
+// There's no region prefetching to guard: neighbours are spawned on demand,
+// as starting them ahead of players would multiply the pods running
+func runLeaderElection(ctx context.Context) {
+	// Local regions belong to this process alone, so there's nobody to elect
+	if cluster.Local() {
+		isLeader.Set(1)
+		go runWorldClock(ctx)
+		runReaper(ctx)
+		return
+	}
+
+	identity := os.Getenv("POD_NAME") // Set via the downward API
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: "default",
+		},
+		Client:     clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	// RunOrDie returns when leadership is lost; keep competing until shutdown
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			Name:            leaseName,
+			LeaseDuration:   15 * time.Second,
+			RenewDeadline:   10 * time.Second,
+			RetryPeriod:     2 * time.Second,
+			ReleaseOnCancel: true, // Hand over immediately on SIGTERM
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					slog.Info("Became leader, starting background work", "identity", identity)
+					isLeader.Set(1)
+					go runWorldClock(ctx)
+					runReaper(ctx)
+				},
+				OnStoppedLeading: func() {
+					slog.Info("Lost leadership, background work stopped", "identity", identity)
+					isLeader.Set(0)
+					liveRegions.Set(0) // The next leader reports them
+				},
+				OnNewLeader: func(current string) {
+					if current != identity {
+						slog.Info("Following leader", "leader", current)
+					}
+				},
+			},
+		})
+	}
+}
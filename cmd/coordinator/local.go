@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/akos011221/driftscape/internal/cluster"
+)
+
+// With ORCHESTRATOR=local there's no Kubernetes: each region is a child
+// process of this Coordinator, listening on a free local port recorded in
+// cluster.LocalRegionsKey. One Coordinator only, as nobody else can reach
+// its children; meant for a single machine, e.g. the load test's -local.
+
+const (
+	// localStartTimeout bounds the wait for a new region process to listen
+	localStartTimeout = 5 * time.Second
+	// localStopTimeout is how long a region gets to drain before it's killed,
+	// a little over the region's own shutdownTimeout
+	localStopTimeout = 25 * time.Second
+)
+
+// localRegion is a region process this Coordinator started
+type localRegion struct {
+	x, y    int
+	addr    string
+	cmd     *exec.Cmd
+	started time.Time
+	ready   chan struct{} // Closed once it listens, or failed to (err)
+	err     error
+	exited  chan struct{} // Closed once the process is gone
+}
+
+var (
+	// regionBin is the region binary to start (REGION_BIN, else "region" on PATH)
+	regionBin = "region"
+
+	localMu      sync.Mutex
+	localRegions = map[string]*localRegion{} // By pod name, e.g. "region-2-4"
+)
+
+func setupLocal(ctx context.Context) {
+	// Find the region binary and forget addresses left by an earlier run
+	if v := os.Getenv("REGION_BIN"); v != "" {
+		regionBin = v
+	}
+	path, err := exec.LookPath(regionBin)
+	if err != nil {
+		panic("Region binary not found (set REGION_BIN): " + err.Error())
+	}
+	regionBin = path
+	if err := rdb.Del(ctx, cluster.LocalRegionsKey).Err(); err != nil {
+		panic("Clearing local regions failed: " + err.Error())
+	}
+	slog.Info("Running regions as local processes", "binary", regionBin)
+}
+
+func freeAddr() (string, error) {
+	// A local port nothing is listening on right now
+	// Example: "127.0.0.1:40123"
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+func spawnLocal(ctx context.Context, podName string, x, y int) error {
+	// Start a region process for (x,y) and wait until it listens
+	// One already running is kept: unlike a Deployment it can't be half-made,
+	// as a process that dies is forgotten at once
+	// Example: "region-2-4" -> region binary with REGION_X=2 REGION_Y=4 on 127.0.0.1:40123
+	localMu.Lock()
+	if rg := localRegions[podName]; rg != nil {
+		localMu.Unlock()
+		return rg.wait(ctx) // Another request may still be starting it
+	}
+	rg := &localRegion{x: x, y: y, started: time.Now(), ready: make(chan struct{}), exited: make(chan struct{})}
+	localRegions[podName] = rg
+	localMu.Unlock()
+
+	rg.err = rg.start(ctx, podName)
+	close(rg.ready)
+	if rg.err != nil {
+		localMu.Lock()
+		if localRegions[podName] == rg {
+			delete(localRegions, podName)
+		}
+		localMu.Unlock()
+		if rg.cmd != nil && rg.cmd.Process != nil {
+			rg.cmd.Process.Kill()
+		}
+	}
+	return rg.err
+}
+
+func (rg *localRegion) wait(ctx context.Context) error {
+	select {
+	case <-rg.ready:
+		return rg.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (rg *localRegion) start(ctx context.Context, podName string) error {
+	addr, err := freeAddr()
+	if err != nil {
+		return err
+	}
+	rg.addr = addr
+	// Not tied to ctx: the region outlives the request that spawned it
+	rg.cmd = exec.Command(regionBin)
+	rg.cmd.Env = append(os.Environ(),
+		fmt.Sprintf("REGION_X=%d", rg.x),
+		fmt.Sprintf("REGION_Y=%d", rg.y),
+		"LISTEN_ADDR="+addr,
+		"METRICS_ADDR=127.0.0.1:0", // Many regions share the machine
+	)
+	rg.cmd.Stdout, rg.cmd.Stderr = os.Stdout, os.Stderr // JSON logs, tagged with the region
+	if err := rg.cmd.Start(); err != nil {
+		close(rg.exited)
+		return err
+	}
+	go func() {
+		err := rg.cmd.Wait()
+		close(rg.exited)
+		localMu.Lock()
+		current := localRegions[podName] == rg
+		if current {
+			delete(localRegions, podName)
+		}
+		localMu.Unlock()
+		if current { // Died by itself rather than being stopped
+			slog.Warn("Region process exited", "region", podName, "err", err)
+			rdb.HDel(context.Background(), cluster.LocalRegionsKey, podName)
+		}
+	}()
+
+	// A pod takes a while anyway; a process is up in moments, so wait for it
+	// and the move that spawned it gets a real description
+	deadline := time.Now().Add(localStartTimeout)
+	for {
+		if conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s isn't listening on %s", podName, addr)
+		}
+		select {
+		case <-rg.exited:
+			return fmt.Errorf("%s exited on start", podName)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return rdb.HSet(ctx, cluster.LocalRegionsKey, podName, addr).Err()
+}
+
+func stopLocal(ctx context.Context, podName string) error {
+	// Ask a region process to drain and exit; it's killed if it takes too long
+	// Returns at once, like deleting a Deployment
+	localMu.Lock()
+	rg := localRegions[podName]
+	delete(localRegions, podName)
+	localMu.Unlock()
+	if err := rdb.HDel(ctx, cluster.LocalRegionsKey, podName).Err(); err != nil {
+		slog.ErrorContext(ctx, "Failed to forget local region", "region", podName, "err", err)
+	}
+	if rg == nil {
+		slog.DebugContext(ctx, "Nothing to delete", "kind", "process", "name", podName)
+		return nil
+	}
+	if err := rg.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return err
+	}
+	go func() {
+		select {
+		case <-rg.exited:
+		case <-time.After(localStopTimeout):
+			slog.Warn("Region process didn't stop, killing it", "region", podName)
+			rg.cmd.Process.Kill()
+		}
+	}()
+	return nil
+}
+
+func stopLocalRegions() {
+	// Stop every region process and wait for them, when the Coordinator exits
+	localMu.Lock()
+	var all []*localRegion
+	for name, rg := range localRegions {
+		all = append(all, rg)
+		delete(localRegions, name)
+	}
+	localMu.Unlock()
+	for _, rg := range all {
+		rg.cmd.Process.Signal(syscall.SIGTERM)
+	}
+	for _, rg := range all {
+		select {
+		case <-rg.exited:
+		case <-time.After(localStopTimeout):
+			rg.cmd.Process.Kill()
+		}
+	}
+	rdb.Del(context.Background(), cluster.LocalRegionsKey)
+}
+
+func localRunning(podName string) bool {
+	localMu.Lock()
+	defer localMu.Unlock()
+	return localRegions[podName] != nil
+}
+
+func localRegionList() []runningRegion {
+	localMu.Lock()
+	defer localMu.Unlock()
+	list := make([]runningRegion, 0, len(localRegions))
+	for name, rg := range localRegions {
+		list = append(list, runningRegion{name: name, x: rg.x, y: rg.y, created: rg.started})
+	}
+	return list
+}
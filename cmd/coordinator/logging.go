@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/akos011221/driftscape/internal/telemetry"
+)
+
+// statusRecorder remembers the status code a handler wrote
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the real writer, e.g. to flush /events
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// withRequestID tags each request with an ID and logs its outcome
+// A client's own ID is kept only if it's short and plain, as it ends up in
+// every log line and in calls to region pods
+// Example: Client sends no header, or a 2KB one -> ID "9f2c..." is generated and echoed back
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(telemetry.RequestIDHeader)
+		if !telemetry.ValidRequestID(id) {
+			id = telemetry.NewRequestID()
+		}
+		w.Header().Set(telemetry.RequestIDHeader, id)
+		ctx := telemetry.WithRequestID(r.Context(), id)
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if r.URL.Path == "/metrics" || r.URL.Path == "/healthz" {
+			level = slog.LevelDebug // Scrapes and probes would drown everything else
+		}
+		slog.Log(ctx, level, "request handled",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"query", r.URL.RawQuery,
+			"status", rec.status,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// requestIDInterceptor forwards the request ID to region pods as gRPC metadata
+// Example: Region logs for GetDescription(2,4) share the /move request's ID
+func requestIDInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := telemetry.RequestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, telemetry.RequestIDHeader, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
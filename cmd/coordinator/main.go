@@ -3,72 +3,245 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"github.com/akos011221/driftscape/internal/cluster"
+	"github.com/akos011221/driftscape/internal/telemetry"
+	"github.com/akos011221/driftscape/internal/world"
 	pb "github.com/akos011221/driftscape/proto"
 )
 
 var (
 	rdb       *redis.Client
-	clientset *kubernetes.Clientset
-	domain    = "default.svc.cluster.local"
+	clientset *kubernetes.Clientset // Nil with ORCHESTRATOR=local
+	tracer    = otel.Tracer("github.com/akos011221/driftscape/cmd/coordinator")
+	draining  atomic.Bool // Set on SIGTERM so readiness fails first
+
+	// shutdownTimeout bounds how long in-flight moves may take to finish
+	shutdownTimeout = 25 * time.Second
 )
 
 func main() {
+	// Log JSON to stdout, level from LOG_LEVEL
+	telemetry.SetupLogging("coordinator")
+
+	// Export traces (OTLP collector or stdout, see telemetry.SetupTracing)
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), "driftscape-coordinator")
+	if err != nil {
+		panic("Tracing setup failed: " + err.Error())
+	}
+	defer shutdownTracing(context.Background())
+
+	if v := os.Getenv("DAY_LENGTH"); v != "" {
+		dayLength, err = time.ParseDuration(v)
+		if err != nil || dayLength < world.MinutesPerDay*time.Millisecond {
+			slog.Error("Bad DAY_LENGTH", "value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	if v := os.Getenv("WORLD_SEED"); v != "" {
+		worldSeed, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			slog.Error("Bad WORLD_SEED", "value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	if v := os.Getenv("HOME_POINT"); v != "" {
+		if _, err := fmt.Sscanf(v, "%d,%d", &homeX, &homeY); err != nil {
+			slog.Error("Bad HOME_POINT, want x,y", "value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	adminToken = os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" {
+		slog.Info("ADMIN_TOKEN not set, admin commands disabled")
+	}
+
 	// Connect to Redis for persistent storage
 	// Example: redis.default.svc.cluster.local:6379 holds "user:position" -> "2,3"
+	// (positions are "<player>:position"; "user" is the default player)
 	rdb = redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("redis.%s:6379", domain), // Service DNS in K8s
+		Addr: cluster.RedisAddr(), // Service DNS in K8s unless REDIS_ADDR says otherwise
 	})
-	_, err := rdb.Ping(context.Background()).Result()
+	rdb.AddHook(redisMetricsHook{})
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		panic("Redis tracing failed: " + err.Error())
+	}
+	_, err = rdb.Ping(context.Background()).Result()
 	if err != nil {
 		panic("Redis connection failed: " + err.Error())
 	}
 
-	// Connect to Kubernetes (in-cluster config)
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		panic("Kubernetes config failed: " + err.Error())
+	// Connect to Kubernetes (in-cluster config), or run regions as local
+	// processes on this machine (ORCHESTRATOR=local, see local.go)
+	if cluster.Local() {
+		setupLocal(context.Background())
+	} else {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			panic("Kubernetes config failed: " + err.Error())
+		}
+		clientset, err = kubernetes.NewForConfig(config)
+		if err != nil {
+			panic("Kubernetes client failed: " + err.Error())
+		}
 	}
-	clientset, err = kubernetes.NewForConfig(config)
-	if err != nil {
-		panic("Kubernetes client failed: " + err.Error())
+
+	http.Handle("/look", instrument("look", lookHandler))
+	http.Handle("/move", instrument("move", moveHandler))
+	http.Handle("/position", instrument("position", positionHandler))
+	http.Handle("/map", instrument("map", mapHandler))
+	http.Handle("/take", instrument("take", takeHandler))
+	http.Handle("/drop", instrument("drop", dropHandler))
+	http.Handle("/inventory", instrument("inventory", inventoryHandler))
+	http.Handle("/enter", instrument("enter", enterHandler))
+	http.Handle("/exit", instrument("exit", exitHandler))
+	http.Handle("/say", instrument("say", chatHandler("say")))
+	http.Handle("/shout", instrument("shout", chatHandler("shout")))
+	http.Handle("/global", instrument("global", chatHandler("global")))
+	http.Handle("/build", instrument("build", buildHandler))
+	http.Handle("/admin/remove", instrument("admin_remove", adminRemoveHandler))
+	http.Handle("/name", instrument("name", nameHandler))
+	http.Handle("/gazetteer", instrument("gazetteer", gazetteerHandler))
+	http.Handle("/vitals", instrument("vitals", vitalsHandler))
+	http.Handle("/rest", instrument("rest", restHandler))
+	http.Handle("/eat", instrument("eat", eatHandler))
+	http.Handle("/quests", instrument("quests", questsHandler))
+	http.Handle("/quests/accept", instrument("quest_accept", acceptQuestHandler))
+	http.Handle("/quests/abandon", instrument("quest_abandon", abandonQuestHandler))
+	http.Handle("/journal", instrument("journal", journalHandler))
+	http.Handle("/fight", instrument("fight", fightHandler))
+	http.Handle("/gather", instrument("gather", gatherHandler))
+	http.Handle("/craft", instrument("craft", craftHandler))
+	http.Handle("/recipes", instrument("recipes", recipesHandler))
+	http.Handle("/travel/cancel", instrument("travel_cancel", cancelTravelHandler))
+	http.HandleFunc("/travel", travelHandler) // Streams until arrival, like /events
+	http.HandleFunc("/events", eventsHandler) // Long-lived; counted by driftscape_event_streams
+	http.Handle("/metrics", promhttp.Handler())
+	http.HandleFunc("/healthz", healthzHandler)
+
+	addr := os.Getenv("LISTEN_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+	server := &http.Server{
+		Addr: addr,
+		Handler: otelhttp.NewHandler(withRequestID(http.DefaultServeMux), "coordinator",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.URL.Path }),
+			otelhttp.WithFilter(func(r *http.Request) bool { return r.URL.Path != "/metrics" }), // Skip scrapes
+		),
+	}
+	server.RegisterOnShutdown(func() { close(closingStreams) })
+
+	// Stop on SIGTERM (Kubernetes) or SIGINT (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	go func() {
+		slog.Info("Coordinator running", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Coordinator stopped", "err", err)
+			stop()
+		}
+	}()
+
+	// Every replica relays chat to its own clients
+	go runChatRelay(ctx)
+
+	// Replicas share one Lease; only the holder runs background work
+	go runLeaderElection(ctx)
+	<-ctx.Done()
+
+	// Drain: fail readiness, stop accepting, let in-flight moves finish
+	// Example: A /move mid-spawnRegion completes and saves its position first
+	slog.Info("Shutting down, draining in-flight requests")
+	draining.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Drain timed out, closing remaining connections", "err", err)
+		server.Close()
 	}
 
-	http.HandleFunc("/look", lookHandler)
-	http.HandleFunc("/move", moveHandler)
-	http.HandleFunc("/position", positionHandler)
+	// Local regions are this process's children; they go down with it
+	if cluster.Local() {
+		stopLocalRegions()
+	}
 
-	fmt.Println("Coordinator running on :8080")
-	http.ListenAndServe(":8080", nil)
+	// Handlers write to Redis synchronously, so once they are done the only
+	// thing left is to close the client after its queued commands complete
+	if err := rdb.Close(); err != nil {
+		slog.Error("Closing Redis failed", "err", err)
+	}
+	slog.Info("Coordinator stopped")
+}
+
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	// Readiness for the Service: 503 once draining so no new moves arrive
+	// Example: SIGTERM received -> "draining" with status 503
+	if draining.Load() {
+		http.Error(w, "draining", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprint(w, "ok")
 }
 
 func positionHandler(w http.ResponseWriter, r *http.Request) {
 	// Send last known position to Client
 	// Example: Client gets "2,3" from Redis
-	pos, err := rdb.Get(context.Background(), "user:position").Result()
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	sub, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
 	if err == redis.Nil {
-		fmt.Fprintf(w, "0,0") // Center, if no position
+		writePosition(w, r, 0, 0, sub) // Center, if no position
 	} else if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
 		http.Error(w, "Redis error", 500)
 		return
 	} else {
-		fmt.Fprintf(w, pos) // Send last known position
+		x, y := parsePosition(pos)
+		writePosition(w, r, x, y, sub) // Send last known position
 	}
 }
 
@@ -80,14 +253,35 @@ func lookHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	// Inside a cave or ruins, looking around describes the room
+	// Example: "alice" in "cave/2" at (2,3) -> "You're in a crystal grotto..."
+	if sub, err := getSublocation(r.Context(), player); err != nil {
+		slog.WarnContext(r.Context(), "Reading sublocation failed", "err", err)
+	} else if sub != "" {
+		if pos, _ := rdb.Get(r.Context(), positionKey(player)).Result(); pos == fmt.Sprintf("%d,%d", x, y) {
+			room, err := getRoom(r.Context(), x, y, sub)
+			if err == nil {
+				writeReply(w, r, roomReply(x, y, storedTerrain(r.Context(), x, y), room))
+				return
+			}
+			slog.WarnContext(r.Context(), "Room unavailable, describing outside", "sublocation", sub, "err", err)
+		}
+	}
 
 	// Check or spawn region in Redis/K8s
 	// Example: "region:2,3" -> "forest" or spawn pod
 	key := fmt.Sprintf("region:%d,%d", x, y)
-	regionData, err := rdb.Get(context.Background(), key).Result()
-	if err == redis.Nil || !regionExists(x, y) {
-		regionData = spawnRegion(x, y)
+	regionData, err := rdb.Get(r.Context(), key).Result()
+	if err == redis.Nil || !regionExists(r.Context(), x, y) {
+		regionData = spawnRegion(r.Context(), x, y)
 	} else if err != nil {
+		slog.ErrorContext(r.Context(), "Reading region failed", "key", key, "err", err)
 		http.Error(w, "Redis error", 500)
 		return
 	}
@@ -95,12 +289,27 @@ func lookHandler(w http.ResponseWriter, r *http.Request) {
 	// Call Region pod via gRPC
 	// Example: Dial "region-2-3:8081", get "forest with a river"
 	podName := fmt.Sprintf("region-%d-%d", x, y)
-	desc, err := getRegionDescription(podName, x, y)
+	desc, err := getRegionDescription(r.Context(), podName, x, y)
 	if err != nil {
-		fmt.Fprintf(w, "You are in a %s at (%d,%d)", regionData, x, y)
+		slog.WarnContext(r.Context(), "Region unreachable, using stored terrain", "region", podName, "err", err)
+		writeReply(w, r, reply{X: x, Y: y, Terrain: regionData,
+			Message: fmt.Sprintf("You are in a %s at (%d,%d)", regionData, x, y)})
 		return
 	}
-	fmt.Fprintf(w, "You're in a %s at (%d,%d)", desc, x, y)
+	// Looking around where you stand maps it and keeps you visible to others;
+	// looking at anywhere else doesn't count as having been there
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if atX, atY := parsePosition(pos); (err == nil || err == redis.Nil) && atX == x && atY == y { // New players start at 0,0
+		recordDiscovery(r.Context(), player, x, y, desc.Terrain)
+		touchPresence(r.Context(), player, x, y)
+	}
+	ground, present, built, growing := stacks(desc.Items), npcs(desc.Npcs), structures(desc.Structures), resourcesOf(desc.Resources)
+	here, nearby := lookAround(r.Context(), player, x, y, desc.Weather)
+	name := cellLandmark(r.Context(), x, y)
+	writeReply(w, r, reply{X: x, Y: y, Terrain: desc.Terrain, Items: ground, NPCs: present, Conditions: desc.Conditions, Weather: skyOf(desc.Weather),
+		Players: here, Nearby: nearby, Structures: built, Landmark: name, Resources: growing,
+		Message: fmt.Sprintf("You're in a %s at (%d,%d)", desc.Terrain, x, y) + seeWeather(desc.Weather) + seeLandmark(name) + seeConditions(desc.Conditions) +
+			seeStructures(built) + seeItems(ground) + seeResources(growing) + seeNPCs(present) + seePresence(here, nearby)})
 }
 
 func moveHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,45 +320,167 @@ func moveHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	rep, merr := doMove(r.Context(), player, x, y)
+	if merr != nil {
+		http.Error(w, merr.msg, merr.code)
+		return
+	}
+	writeReply(w, r, rep)
+}
 
-	// Clean up old position’s pod
+// moveError is a refused or failed move, with the HTTP status to answer with
+type moveError struct {
+	code int
+	msg  string
+}
+
+// doMove moves a player one cell, spawning its region, and describes it
+// Example: alice at (2,3) -> (2,4) "You moved to a plains at (2,4)..."
+func doMove(ctx context.Context, player string, x, y int) (reply, *moveError) {
+	// Leave caves and ruins the way you came in
+	// Example: "alice" in "cave/2" -> refused until she exits twice
+	sub, err := getSublocation(ctx, player)
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading sublocation failed", "err", err)
+		return reply{}, &moveError{500, "Redis error"}
+	}
+	if sub != "" {
+		kind, _, _ := strings.Cut(sub, "/")
+		return reply{}, &moveError{400, fmt.Sprintf("You're inside the %s; exit first!", kind)}
+	}
+
+	// Nobody walks away from a fight; fleeing is a turn of its own
+	if at, err := fighting(ctx, player); err != nil {
+		slog.ErrorContext(ctx, "Reading fight failed", "err", err)
+		return reply{}, &moveError{500, "Redis error"}
+	} else if at != "" {
+		return reply{}, &moveError{400, "You're in a fight! Attack, flee or use an item."}
+	}
+
+	// Remember old position so its pod can be cleaned up
 	// Example: Was at "2,3", now "2,4"—delete region-2-3
-	oldPos, err := rdb.Get(context.Background(), "user:position").Result()
+	oldPos, err := rdb.Get(ctx, positionKey(player)).Result()
 	if err != nil && err != redis.Nil {
-		http.Error(w, "Redis error", 500)
-		return
+		slog.ErrorContext(ctx, "Reading position failed", "err", err)
+		return reply{}, &moveError{500, "Redis error"}
 	}
 
-	// If there's an old position, clean up its pod
-	if oldPos != "" && oldPos != fmt.Sprintf("%d,%d", x, y) {
-		oldX, oldY := parsePosition(oldPos)
-		deleteRegion(oldX, oldY)
+	// Players walk one cell at a time, diagonals included
+	// Example: From "2,3" to "3,4" is fine, to "2,5" is refused
+	fromX, fromY := parsePosition(oldPos) // New players start at 0,0
+	if !adjacent(fromX, fromY, x, y) {
+		return reply{}, &moveError{400, fmt.Sprintf("Too far! You can only move to a neighbouring cell of (%d,%d)", fromX, fromY)}
+	}
+
+	// Walking tires you; check before starting a region you can't reach
+	// Example: 3 stamina left, the forest at (2,4) takes 10 -> refused
+	terrain := world.Terrain(x, y)
+	cost := moveStamina(ctx, x, y, terrain)
+	if v, err := readVitals(ctx, player); err != nil {
+		slog.ErrorContext(ctx, "Reading vitals failed", "err", err)
+		return reply{}, &moveError{500, "Redis error"}
+	} else if why := exhausted(v, terrain, cost); why != "" {
+		return reply{}, &moveError{400, why}
 	}
 
 	// Check or spawn new region
 	// Example: "region:2,4" -> "plains" or spawn pod
 	key := fmt.Sprintf("region:%d,%d", x, y)
-	regionData, err := rdb.Get(context.Background(), key).Result()
-	if err == redis.Nil || !regionExists(x, y) {
-		regionData = spawnRegion(x, y)
+	regionData, err := rdb.Get(ctx, key).Result()
+	if err == redis.Nil || !regionExists(ctx, x, y) {
+		regionData = spawnRegion(ctx, x, y)
 	} else if err != nil {
-		http.Error(w, "Redis error", 500)
-		return
+		slog.ErrorContext(ctx, "Reading region failed", "key", key, "err", err)
+		return reply{}, &moveError{500, "Redis error"}
 	}
 
-	// Save new position
-	// Example: "user:position" -> "2,4" in Redis
-	rdb.Set(context.Background(), "user:position", fmt.Sprintf("%d,%d", x, y), 0)
-
-	// Get description via gRPC
-	// Example: "region-2-4:8081" -> "plains with a hill"
+	// Get description via gRPC before moving, since the weather may bar the way
+	// Example: "region-2-4:8081" -> "plains with a river" in a storm -> refused
 	podName := fmt.Sprintf("region-%d-%d", x, y)
-	desc, err := getRegionDescription(podName, x, y)
+	desc, descErr := getRegionDescription(ctx, podName, x, y)
+	tooled := ""
+	if descErr == nil && desc.Weather != nil && desc.Weather.Blocked != "" {
+		// A boat or climbing gear may still get the player through
+		// Example: a swollen river, alice carries a boat -> "You row across the swollen river"
+		past := hazardsPast(readInventory(ctx, player))
+		now, err := world.ReadClock(ctx, rdb)
+		if len(past) == 0 || err != nil || blockedAt(ctx, world.Point{X: x, Y: y}, now, past) != "" {
+			return reply{}, &moveError{400, desc.Weather.Blocked} // The reaper removes the region if nobody comes
+		}
+		tooled = seeTools(desc.Terrain, past)
+	}
+
+	v, why, err := spendStamina(ctx, player, terrain, cost)
 	if err != nil {
-		fmt.Fprintf(w, "You moved to a %s at (%d,%d)", regionData, x, y)
-		return
+		slog.ErrorContext(ctx, "Spending stamina failed", "err", err)
+		return reply{}, &moveError{500, "Redis error"}
+	} else if why != "" {
+		return reply{}, &moveError{400, why} // Spent elsewhere meanwhile, e.g. on another trip
 	}
-	fmt.Fprintf(w, "You moved to a %s at (%d,%d)", desc, x, y)
+	if merr := relocate(ctx, player, oldPos, x, y); merr != nil {
+		return reply{}, merr
+	}
+	notes := advanceQuests(ctx, player, questEvent{X: x, Y: y})
+
+	if descErr != nil {
+		slog.WarnContext(ctx, "Region unreachable, using stored terrain", "region", podName, "err", descErr)
+		return reply{X: x, Y: y, Terrain: regionData, Vitals: v.signs(),
+			Message: fmt.Sprintf("You moved to a %s at (%d,%d)", regionData, x, y) + seeVitals(v) + seeQuests(notes)}, nil
+	}
+	recordDiscovery(ctx, player, x, y, desc.Terrain)
+	foe := encounter(ctx, player, x, y)
+	ground, present, built, growing := stacks(desc.Items), npcs(desc.Npcs), structures(desc.Structures), resourcesOf(desc.Resources)
+	here, nearby := lookAround(ctx, player, x, y, desc.Weather)
+	name := cellLandmark(ctx, x, y)
+	return reply{X: x, Y: y, Terrain: desc.Terrain, Items: ground, NPCs: present, Conditions: desc.Conditions, Weather: skyOf(desc.Weather),
+		Players: here, Nearby: nearby, Structures: built, Landmark: name, Vitals: v.signs(), Fight: viewFight(foe), Resources: growing,
+		Message: fmt.Sprintf("You moved to a %s at (%d,%d)", desc.Terrain, x, y) + tooled + seeWeather(desc.Weather) + seeLandmark(name) + seeConditions(desc.Conditions) +
+			seeStructures(built) + seeItems(ground) + seeResources(growing) + seeNPCs(present) + seePresence(here, nearby) + seeVitals(v) + seeQuests(notes) + seeFight(foe)}, nil
+}
+
+// relocate saves a player's new position, updates presence and cleans up
+// the region they left if nobody else stands there
+// Example: alice "2,3" -> (2,4): region-2-3 deleted if it's now empty
+func relocate(ctx context.Context, player, oldPos string, x, y int) *moveError {
+	// Save new position, moving the player between cells' occupants with it
+	// Example: "user:position" -> "2,4", "occupants:2,3" -> "occupants:2,4"
+	pos := fmt.Sprintf("%d,%d", x, y)
+	moved := oldPos != "" && oldPos != pos
+	oldX, oldY := parsePosition(oldPos)
+	var left *redis.IntCmd
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, positionKey(player), pos, 0)
+		pipe.SAdd(ctx, occupantsKey(x, y), player)
+		if moved {
+			pipe.SRem(ctx, occupantsKey(oldX, oldY), player)
+			left = pipe.SCard(ctx, occupantsKey(oldX, oldY))
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Saving position failed", "x", x, "y", y, "err", err)
+		return &moveError{500, "Redis error"}
+	}
+
+	// Show up in the new cell's presence, and no longer in the old one
+	touchPresence(ctx, player, x, y)
+	if !moved {
+		return nil
+	}
+	leavePresence(ctx, player, oldX, oldY)
+
+	// If nobody else stands on the old position, clean up its pod
+	// Players who haven't moved since occupants were kept aren't counted;
+	// their region is spawned again when they next act (see playerRegion)
+	if left.Val() == 0 {
+		deleteRegion(ctx, oldX, oldY)
+	}
+	return nil
 }
 
 func getXY(r *http.Request) (int, int, error) {
@@ -168,10 +499,74 @@ func getXY(r *http.Request) (int, int, error) {
 	return x, y, nil
 }
 
-func spawnRegion(x, y int) string {
+// playerPattern keeps player names safe to embed in Redis keys
+var playerPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+func getPlayer(r *http.Request) (string, error) {
+	// Parse the player name, defaulting to the original single player
+	// Example: "?player=alice" -> "alice", no param -> "user"
+	player := r.URL.Query().Get("player")
+	if player == "" {
+		return "user", nil
+	}
+	if !playerPattern.MatchString(player) {
+		return "", fmt.Errorf("Bad player name!")
+	}
+	return player, nil
+}
+
+func positionKey(player string) string {
+	// Redis key holding a player's position
+	// Example: "alice" -> "alice:position"
+	return player + ":position"
+}
+
+func occupantsKey(x, y int) string {
+	// Redis set of players standing in a cell, kept with their positions
+	// Unlike presence it doesn't lapse while a player idles
+	// Example: "occupants:2,4" -> {"alice", "bob"}
+	return fmt.Sprintf("occupants:%d,%d", x, y)
+}
+
+func spawnRegion(ctx context.Context, x, y int) string {
 	// Create a new region pod with HPA
 	// Example: Spawns "region-2-4" pod + service in OKE
+	ctx, span := tracer.Start(ctx, "spawnRegion", trace.WithAttributes(
+		attribute.Int("region.x", x), attribute.Int("region.y", y),
+	))
+	defer span.End()
+	start := time.Now()
+	defer observeSince(regionSpawnDuration, start)
+
 	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var spawnErr error
+	if cluster.Local() {
+		if spawnErr = spawnLocal(ctx, podName, x, y); spawnErr != nil {
+			slog.ErrorContext(ctx, "Failed to spawn region", "region", podName, "err", spawnErr)
+		}
+	} else {
+		spawnErr = spawnDeployment(ctx, podName, x, y)
+	}
+	regionSpawns.WithLabelValues(resultLabel(spawnErr)).Inc()
+	if spawnErr != nil {
+		span.RecordError(spawnErr)
+		span.SetStatus(codes.Error, "spawn failed")
+	} else {
+		slog.InfoContext(ctx, "Region spawned", "region", podName)
+	}
+
+	// Save basic region type to Redis (pod will refine it)
+	regionType := "unknown" // Placeholder, pod sets real type
+	if err := rdb.Set(ctx, fmt.Sprintf("region:%d,%d", x, y), regionType, 0).Err(); err != nil {
+		slog.ErrorContext(ctx, "Failed to save region placeholder", "region", podName, "err", err)
+	}
+	return regionType
+}
+
+func spawnDeployment(ctx context.Context, podName string, x, y int) error {
+	// Create the region's Deployment, HPA and Service in Kubernetes
+	// Example: "region-2-4" Deployment, "region-2-4-hpa" and "region-2-4" Service
+	var spawnErr error
 	// Sanitize x, y for labels (replace negative with 'n')
 	xLabel := strconv.Itoa(x)
 	if x < 0 {
@@ -183,9 +578,9 @@ func spawnRegion(x, y int) string {
 	}
 
 	// Check if Deployment exists, delete if broken
-	_, err := clientset.AppsV1().Deployments("default").Get(context.Background(), podName, metav1.GetOptions{})
+	_, err := clientset.AppsV1().Deployments("default").Get(ctx, podName, metav1.GetOptions{})
 	if err == nil {
-		deleteRegion(x, y) // Clean up stale Deployment
+		deleteRegion(ctx, x, y) // Clean up stale Deployment
 	}
 
 	deployment := &appsv1.Deployment{
@@ -208,6 +603,10 @@ func spawnRegion(x, y int) string {
 						"x":   xLabel,
 						"y":   yLabel,
 					},
+					Annotations: map[string]string{ // Scraped by Prometheus
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "9091",
+					},
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
@@ -217,8 +616,17 @@ func spawnRegion(x, y int) string {
 							Env: []corev1.EnvVar{
 								{Name: "REGION_X", Value: strconv.Itoa(x)},
 								{Name: "REGION_Y", Value: strconv.Itoa(y)},
+								// Regions export traces wherever the Coordinator does
+								{Name: "OTEL_TRACES_EXPORTER", Value: os.Getenv("OTEL_TRACES_EXPORTER")},
+								{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")},
+								{Name: "LOG_LEVEL", Value: os.Getenv("LOG_LEVEL")},
+								{Name: "WORLD_SEED", Value: os.Getenv("WORLD_SEED")},
+								{Name: "SIM_TICK", Value: os.Getenv("SIM_TICK")},
+							},
+							Ports: []corev1.ContainerPort{
+								{ContainerPort: 8081},
+								{Name: "metrics", ContainerPort: 9091},
 							},
-							Ports: []corev1.ContainerPort{{ContainerPort: 8081}},
 							//ReadinessProbe: &corev1.Probe{
 							//	ProbeHandler: corev1.ProbeHandler{
 							//		GRPC: &corev1.GRPCAction{
@@ -234,17 +642,26 @@ func spawnRegion(x, y int) string {
 									corev1.ResourceCPU: resourceMustParse("100m"),
 								},
 							},
+							// Give the Service time to drop this pod before SIGTERM,
+							// so the Coordinator's last GetDescription isn't cut off
+							Lifecycle: &corev1.Lifecycle{
+								PreStop: &corev1.LifecycleHandler{
+									Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}},
+								},
+							},
 						},
 					},
+					TerminationGracePeriodSeconds: int64Ptr(30),
 				},
 			},
 		},
 	}
 
 	// Create Deployment in default namespace
-	_, err = clientset.AppsV1().Deployments("default").Create(context.Background(), deployment, metav1.CreateOptions{})
+	_, err = clientset.AppsV1().Deployments("default").Create(ctx, deployment, metav1.CreateOptions{})
 	if err != nil {
-		fmt.Println("Failed to spawn region:", err)
+		slog.ErrorContext(ctx, "Failed to spawn region", "region", podName, "err", err)
+		spawnErr = err
 	}
 
 	// Add HPA for scaling
@@ -264,9 +681,10 @@ func spawnRegion(x, y int) string {
 			TargetCPUUtilizationPercentage: int32Ptr(50),
 		},
 	}
-	_, err = clientset.AutoscalingV1().HorizontalPodAutoscalers("default").Create(context.Background(), hpa, metav1.CreateOptions{})
+	_, err = clientset.AutoscalingV1().HorizontalPodAutoscalers("default").Create(ctx, hpa, metav1.CreateOptions{})
 	if err != nil {
-		fmt.Println("Failed to create HPA:", err)
+		slog.ErrorContext(ctx, "Failed to create HPA", "region", podName, "err", err)
+		spawnErr = err
 	}
 
 	// Create Service for the pod
@@ -285,45 +703,98 @@ func spawnRegion(x, y int) string {
 			},
 		},
 	}
-	_, err = clientset.CoreV1().Services("default").Create(context.Background(), service, metav1.CreateOptions{})
+	_, err = clientset.CoreV1().Services("default").Create(ctx, service, metav1.CreateOptions{})
 	if err != nil {
-		fmt.Println("Failed to create service:", err)
+		slog.ErrorContext(ctx, "Failed to create service", "region", podName, "err", err)
+		spawnErr = err
 	}
-
-	// Save basic region type to Redis (pod will refine it)
-	regionType := "unknown" // Placeholder, pod sets real type
-	rdb.Set(context.Background(), fmt.Sprintf("region:%d,%d", x, y), regionType, 0)
-	return regionType
+	return spawnErr
 }
 
-func deleteRegion(x, y int) {
+func deleteRegion(ctx context.Context, x, y int) {
 	// Remove a region pod and its HPA
 	// Example: Deletes "region-2-3" and "region-2-3-hpa"
+	ctx, span := tracer.Start(ctx, "deleteRegion", trace.WithAttributes(
+		attribute.Int("region.x", x), attribute.Int("region.y", y),
+	))
+	defer span.End()
+	start := time.Now()
+	defer observeSince(regionDeleteDuration, start)
+
 	podName := fmt.Sprintf("region-%d-%d", x, y)
-	clientset.AppsV1().Deployments("default").Delete(context.Background(), podName, metav1.DeleteOptions{})
-	clientset.CoreV1().Services("default").Delete(context.Background(), podName, metav1.DeleteOptions{})
-	clientset.AutoscalingV1().HorizontalPodAutoscalers("default").Delete(context.Background(), podName+"-hpa", metav1.DeleteOptions{})
+	var err error
+	if cluster.Local() {
+		err = stopLocal(ctx, podName)
+	} else {
+		err = deleteDeployment(ctx, podName)
+	}
+	regionDeletes.WithLabelValues(resultLabel(err)).Inc()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete failed")
+	} else {
+		slog.InfoContext(ctx, "Region deleted", "region", podName)
+	}
+}
+
+func deleteDeployment(ctx context.Context, podName string) error {
+	// Delete the region's Deployment, Service and HPA; the Deployment's error is returned
+	err := clientset.AppsV1().Deployments("default").Delete(ctx, podName, metav1.DeleteOptions{})
+	logDeleteError(ctx, "Deployment", podName, err)
+	svcErr := clientset.CoreV1().Services("default").Delete(ctx, podName, metav1.DeleteOptions{})
+	logDeleteError(ctx, "Service", podName, svcErr)
+	hpaErr := clientset.AutoscalingV1().HorizontalPodAutoscalers("default").Delete(ctx, podName+"-hpa", metav1.DeleteOptions{})
+	logDeleteError(ctx, "HPA", podName+"-hpa", hpaErr)
+	return err
+}
+
+func logDeleteError(ctx context.Context, kind, name string, err error) {
+	// Report a failed delete; already-gone objects are only worth a debug line
+	// Example: Service "region-2-3" not found -> debug, API timeout -> error
+	if err == nil {
+		return
+	}
+	if apierrors.IsNotFound(err) {
+		slog.DebugContext(ctx, "Nothing to delete", "kind", kind, "name", name)
+		return
+	}
+	slog.ErrorContext(ctx, "Failed to delete", "kind", kind, "name", name, "err", err)
 }
 
 func parsePosition(pos string) (int, int) {
 	// Split "x,y" into numbers
-	// Example: "2,3" -> x=2, y=3
+	// Example: "2,3" -> x=2, y=3, garbage -> 0,0
 	parts := strings.Split(pos, ",")
+	if len(parts) != 2 {
+		return 0, 0
+	}
 	x, _ := strconv.Atoi(parts[0])
 	y, _ := strconv.Atoi(parts[1])
 	return x, y
 }
 
-func regionExists(x, y int) bool {
+func adjacent(fromX, fromY, toX, toY int) bool {
+	// A cell is adjacent if it's at most one step away on each axis
+	// Example: (0,0) -> (1,1) is adjacent, (0,0) -> (0,2) is not
+	dx, dy := toX-fromX, toY-fromY
+	return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
+
+func regionExists(ctx context.Context, x, y int) bool {
 	// Check if a region pod exists
 	// Example: Looks for "region-2-4" in OKE
 	podName := fmt.Sprintf("region-%d-%d", x, y)
-	_, err := clientset.AppsV1().Deployments("default").Get(context.Background(), podName, metav1.GetOptions{})
+	if cluster.Local() {
+		return localRunning(podName)
+	}
+	_, err := clientset.AppsV1().Deployments("default").Get(ctx, podName, metav1.GetOptions{})
 	return err == nil
 }
 
 func int32Ptr(i int32) *int32 { return &i }
 
+func int64Ptr(i int64) *int64 { return &i }
+
 func resourceMustParse(s string) resource.Quantity {
 	// Parse resource strings for HPA
 	// Example: "100m" -> 0.1 CPU (100 milliCPU)
@@ -331,27 +802,40 @@ func resourceMustParse(s string) resource.Quantity {
 	return q
 }
 
-func getRegionDescription(podName string, x, y int) (string, error) {
-	// Connect to Region pod via gRPC
-	// Example: Dials "region-2-4:8081", sends x=2, y=4
+func getRegionDescription(ctx context.Context, podName string, x, y int) (*pb.Description, error) {
+	// Call GetDescription
+	// Example: Gets "forest with a river" and its items for (2,4)
+	var desc *pb.Description
+	err := callRegion(ctx, podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		desc, err = client.GetDescription(ctx, &pb.Position{X: int32(x), Y: int32(y)})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get description from %s: %w", podName, err)
+	}
+	return desc, nil
+}
+
+func callRegion(ctx context.Context, podName string, call func(context.Context, pb.RegionServiceClient) error) error {
+	// Connect to Region pod via gRPC and make one call
+	// Example: Dials "region-2-4:8081", runs call with a 5s deadline
+	addr, err := cluster.RegionAddr(ctx, rdb, podName)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", podName, err)
+	}
 	conn, err := grpc.NewClient(
-		fmt.Sprintf("%s.%s:8081", podName, domain),
+		addr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()), // No TLS for simplicity
+		grpc.WithChainUnaryInterceptor(grpcMetricsInterceptor, requestIDInterceptor),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // Carries trace context to the region
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to connect to %s: %v", podName, err)
+		return fmt.Errorf("failed to connect to %s: %v", podName, err)
 	}
 	defer conn.Close()
 
-	client := pb.NewRegionServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-
-	// Call GetDescription
-	// Example: Gets "forest with a river" for (2,4)
-	resp, err := client.GetDescription(ctx, &pb.Position{X: int32(x), Y: int32(y)})
-	if err != nil {
-		return "", fmt.Errorf("failed to get description from %s: %v", podName, err)
-	}
-	return resp.Terrain, nil
+	return call(ctx, pb.NewRegionServiceClient(conn))
 }
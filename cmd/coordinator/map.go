@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// maxMapSpan caps how wide a /map bounding box may be on either axis
+const maxMapSpan = 201
+
+// mapCell is one discovered cell in a /map reply
+type mapCell struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Terrain string `json:"terrain"`
+}
+
+// mapReply is the JSON body returned by /map
+type mapReply struct {
+	X     int       `json:"x"` // Player's current position
+	Y     int       `json:"y"`
+	Cells []mapCell `json:"cells"`
+}
+
+func discoveryKey(player string) string {
+	// Redis hash of cells a player has seen
+	// Example: "alice:map" field "2,4" -> "plains with a hill"
+	return player + ":map"
+}
+
+func recordDiscovery(ctx context.Context, player string, x, y int, terrain string) {
+	// Remember the cell for the player's map; a failure only costs fog
+	err := rdb.HSet(ctx, discoveryKey(player), fmt.Sprintf("%d,%d", x, y), terrain).Err()
+	if err != nil {
+		slog.ErrorContext(ctx, "Saving discovered cell failed", "player", player, "x", x, "y", y, "err", err)
+	}
+}
+
+func mapHandler(w http.ResponseWriter, r *http.Request) {
+	// Send the player's discovered cells inside a bounding box
+	// Example: "?player=alice&minx=-5&miny=-5&maxx=5&maxy=5" -> JSON cells
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	// Current position is the center of the default box
+	px, py := 0, 0
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if err == nil {
+		px, py = parsePosition(pos)
+	}
+
+	half := maxMapSpan / 2
+	minX, errMinX := queryInt(r, "minx", px-half)
+	minY, errMinY := queryInt(r, "miny", py-half)
+	maxX, errMaxX := queryInt(r, "maxx", px+half)
+	maxY, errMaxY := queryInt(r, "maxy", py+half)
+	if errMinX != nil || errMinY != nil || errMaxX != nil || errMaxY != nil {
+		http.Error(w, "Bad bounding box!", 400)
+		return
+	}
+	if minX > maxX || minY > maxY || maxX-minX >= maxMapSpan || maxY-minY >= maxMapSpan {
+		http.Error(w, fmt.Sprintf("Bounding box must be at most %dx%d cells", maxMapSpan, maxMapSpan), 400)
+		return
+	}
+
+	seen, err := rdb.HGetAll(r.Context(), discoveryKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading discovered cells failed", "player", player, "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	reply := mapReply{X: px, Y: py, Cells: []mapCell{}}
+	for cell, terrain := range seen {
+		x, y := parsePosition(cell)
+		if x < minX || x > maxX || y < minY || y > maxY {
+			continue
+		}
+		reply.Cells = append(reply.Cells, mapCell{X: x, Y: y, Terrain: terrain})
+	}
+	// North to south, west to east, like reading the map
+	sort.Slice(reply.Cells, func(i, j int) bool {
+		a, b := reply.Cells[i], reply.Cells[j]
+		if a.Y != b.Y {
+			return a.Y > b.Y
+		}
+		return a.X < b.X
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	// Read an optional integer query param
+	// Example: "?minx=-3" -> -3, missing -> fallback
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics exposed on the Coordinator's /metrics endpoint
+// Example: driftscape_coordinator_requests_total{handler="move",code="200"} 42
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_coordinator_requests_total",
+		Help: "HTTP requests handled by the coordinator, by handler and status code.",
+	}, []string{"handler", "code"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "driftscape_coordinator_request_duration_seconds",
+		Help:    "Latency of coordinator HTTP handlers.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler"})
+
+	regionSpawns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_coordinator_region_spawns_total",
+		Help: "Region spawn attempts, by result (ok or error).",
+	}, []string{"result"})
+
+	regionSpawnDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "driftscape_coordinator_region_spawn_duration_seconds",
+		Help:    "Time spent creating a region's Deployment, HPA and Service.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	regionDeletes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_coordinator_region_deletes_total",
+		Help: "Region delete attempts, by result (ok or error).",
+	}, []string{"result"})
+
+	regionDeleteDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "driftscape_coordinator_region_delete_duration_seconds",
+		Help:    "Time spent removing a region's Deployment, HPA and Service.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "driftscape_coordinator_region_grpc_duration_seconds",
+		Help:    "Latency of gRPC calls to region pods, by method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// liveRegions is set by the leader's reaper from the list it already
+	// makes, so scrapes never reach the Kubernetes API; other replicas show 0
+	// Example: driftscape_coordinator_live_regions 3 while three region pods run
+	liveRegions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "driftscape_coordinator_live_regions",
+		Help: "Region Deployments in the namespace, as of the leader's last reaper pass; 0 on other replicas.",
+	})
+
+	redisErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "driftscape_coordinator_redis_errors_total",
+		Help: "Failed Redis commands, by command name.",
+	}, []string{"command"})
+)
+
+// instrument wraps a handler with request count and latency metrics
+// Example: instrument("look", lookHandler) records under handler="look"
+func instrument(name string, h http.HandlerFunc) http.Handler {
+	labels := prometheus.Labels{"handler": name}
+	return promhttp.InstrumentHandlerDuration(httpDuration.MustCurryWith(labels),
+		promhttp.InstrumentHandlerCounter(httpRequests.MustCurryWith(labels), h))
+}
+
+// observeSince records the seconds elapsed since start
+func observeSince(h prometheus.Observer, start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+// resultLabel turns an error into "ok" or "error" for counters
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// grpcMetricsInterceptor times calls to region pods
+// Example: GetDescription returning Unavailable lands in code="Unavailable"
+func grpcMetricsInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	grpcDuration.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// redisMetricsHook counts failed Redis commands (a missing key is not a failure)
+type redisMetricsHook struct{}
+
+func (redisMetricsHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (redisMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			redisErrors.WithLabelValues(cmd.Name()).Inc()
+		}
+		return err
+	}
+}
+
+func (redisMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+				redisErrors.WithLabelValues(cmd.Name()).Inc()
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// npc is a non-player character in a /look or /move reply
+type npc struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"` // "wanderer", "trader" or "creature"
+	Name string `json:"name"`
+}
+
+func npcs(list []*pb.NPC) []npc {
+	// Convert a region's NPC list for replies
+	// Example: [{npc-2-4-0 trader "a travelling trader"}] -> same, as JSON-friendly structs
+	out := make([]npc, 0, len(list))
+	for _, n := range list {
+		out = append(out, npc{ID: n.Id, Kind: n.Kind, Name: n.Name})
+	}
+	return out
+}
+
+func seeNPCs(present []npc) string {
+	// Sentence appended to look and move messages
+	// Example: [a red fox, a hooded pilgrim] -> ". Also here: a red fox, a hooded pilgrim"
+	if len(present) == 0 {
+		return ""
+	}
+	names := make([]string, len(present))
+	for i, n := range present {
+		names[i] = n.Name
+	}
+	return ". Also here: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+const (
+	// presenceTTL is how long a player counts as present after they last
+	// moved, looked or kept their event stream open
+	presenceTTL = 2 * time.Minute
+
+	// sightRadius is how far away figures can be made out; fog, storms and
+	// the dark cut it to one cell
+	sightRadius = 2
+)
+
+func presenceKey(x, y int) string {
+	// Redis sorted set of players in a cell, scored by when they were last seen
+	// Example: "presence:2,4" -> {"alice": 1717000000, "bob": 1717000042}
+	return fmt.Sprintf("presence:%d,%d", x, y)
+}
+
+func touchPresence(ctx context.Context, player string, x, y int) {
+	// Example: alice moves to (2,4) -> "presence:2,4" alice=now
+	if err := rdb.ZAdd(ctx, presenceKey(x, y), redis.Z{Score: float64(time.Now().Unix()), Member: player}).Err(); err != nil {
+		slog.WarnContext(ctx, "Updating presence failed", "x", x, "y", y, "err", err)
+	}
+}
+
+func leavePresence(ctx context.Context, player string, x, y int) {
+	if err := rdb.ZRem(ctx, presenceKey(x, y), player).Err(); err != nil {
+		slog.WarnContext(ctx, "Clearing presence failed", "x", x, "y", y, "err", err)
+	}
+}
+
+// figures are unnamed players seen in one direction
+type figures struct {
+	Direction string `json:"direction"` // e.g. "north", "southwest"
+	Count     int    `json:"count"`
+}
+
+// presenceAround lists other players at (x,y) and, by direction, within radius
+// Example: bob at (2,4), carol at (2,6), looking from (2,4) -> ["bob"], [{north 1}]
+func presenceAround(ctx context.Context, player string, x, y, radius int) ([]string, []figures, error) {
+	// Ask every cell in range at once, dropping whoever went quiet
+	stale := strconv.FormatInt(time.Now().Add(-presenceTTL).Unix(), 10)
+	pipe := rdb.Pipeline()
+	cells := map[[2]int]*redis.StringSliceCmd{}
+	for dx := -radius; dx <= radius; dx++ {
+		for dy := -radius; dy <= radius; dy++ {
+			key := presenceKey(x+dx, y+dy)
+			pipe.ZRemRangeByScore(ctx, key, "-inf", "("+stale)
+			cells[[2]int{dx, dy}] = pipe.ZRange(ctx, key, 0, -1)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, nil, err
+	}
+
+	var here []string
+	counts := map[string]int{}
+	for d, cmd := range cells {
+		for _, p := range cmd.Val() {
+			switch {
+			case p == player:
+			case d == [2]int{0, 0}:
+				here = append(here, p)
+			default:
+				counts[compass(d[0], d[1])]++
+			}
+		}
+	}
+	sort.Strings(here)
+
+	var nearby []figures
+	for _, d := range directionOrder {
+		if counts[d] > 0 {
+			nearby = append(nearby, figures{Direction: d, Count: counts[d]})
+		}
+	}
+	return here, nearby, nil
+}
+
+// directionOrder lists compass directions clockwise from north
+var directionOrder = []string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+func compass(dx, dy int) string {
+	// Rough direction of an offset, north being +y
+	// Example: (0,2) -> "north", (1,-2) -> "southeast"
+	ns, ew := "", ""
+	switch {
+	case dy > 0:
+		ns = "north"
+	case dy < 0:
+		ns = "south"
+	}
+	switch {
+	case dx > 0:
+		ew = "east"
+	case dx < 0:
+		ew = "west"
+	}
+	return ns + ew
+}
+
+func sightFor(w *pb.Weather) int {
+	// How far a player can see under the region's sky
+	// Example: clear afternoon -> 2, fog or night -> 1
+	if w != nil && (w.Kind == "fog" || w.Kind == "storm" || w.TimeOfDay == "night") {
+		return 1
+	}
+	return sightRadius
+}
+
+func seePresence(here []string, nearby []figures) string {
+	// Sentences appended to look and move messages
+	// Example: ["bob"], [{north 1} {east 2}] ->
+	// ". Other travellers here: bob. You see figures to the north and east"
+	var b strings.Builder
+	if len(here) > 0 {
+		b.WriteString(". Other travellers here: " + strings.Join(here, ", "))
+	}
+	if len(nearby) > 0 {
+		dirs := make([]string, len(nearby))
+		total := 0
+		for i, f := range nearby {
+			dirs[i] = f.Direction
+			total += f.Count
+		}
+		what := "figures"
+		if total == 1 {
+			what = "a figure"
+		}
+		b.WriteString(fmt.Sprintf(". You see %s to the %s", what, joinAnd(dirs)))
+	}
+	return b.String()
+}
+
+func joinAnd(list []string) string {
+	// Example: ["north", "east", "south"] -> "north, east and south"
+	if len(list) <= 1 {
+		return strings.Join(list, "")
+	}
+	return strings.Join(list[:len(list)-1], ", ") + " and " + list[len(list)-1]
+}
+
+// lookAround gathers presence for a reply, logging rather than failing,
+// since a look without travellers is still a look
+func lookAround(ctx context.Context, player string, x, y int, w *pb.Weather) ([]string, []figures) {
+	here, nearby, err := presenceAround(ctx, player, x, y, sightFor(w))
+	if err != nil {
+		slog.WarnContext(ctx, "Reading presence failed", "x", x, "y", y, "err", err)
+	}
+	return here, nearby
+}
@@ -0,0 +1,542 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/akos011221/driftscape/internal/items"
+	"github.com/akos011221/driftscape/internal/world"
+)
+
+const (
+	questRadius     = 4 // How far away a quest can send you, diagonals included
+	maxActiveQuests = 5
+)
+
+// sights are features worth a visit quest, and how a quest names them
+var sights = []struct{ word, name string }{
+	{"ruins", "the ancient ruins"},
+	{"cave", "the cave"},
+	{"river", "the river"},
+	{"with a hill", "the lone hill"},
+}
+
+// questRewards are extras a quest may pay besides coins
+var questRewards = []string{"torch", "fish", "crystal", "herb"}
+
+var questsCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "driftscape_quests_completed_total",
+	Help: "Quests completed by players on this replica, by kind.",
+}, []string{"kind"})
+
+// quest is a task offered at a cell, and a player's progress on it once taken
+type quest struct {
+	ID     string        `json:"id"`   // Cell and offer it came from, e.g. "2,4#1"
+	Kind   string        `json:"kind"` // "visit" or "fetch"
+	Text   string        `json:"text"` // e.g. "Find the ancient ruins two cells east"
+	GiverX int           `json:"giver_x"`
+	GiverY int           `json:"giver_y"`
+	X      int           `json:"x"` // Where to go: the sight, or where the items are found
+	Y      int           `json:"y"`
+	Item   string        `json:"item,omitempty"` // Fetch: what to bring back to the giver
+	Count  int           `json:"count,omitempty"`
+	Have   int           `json:"have,omitempty"` // Fetch: picked up since accepting
+	Reward []items.Stack `json:"reward"`
+	Done   bool          `json:"done,omitempty"`
+}
+
+func (q quest) progress() string {
+	// Where a player stands with an active quest
+	// Example: fetch herb 1/3 -> "herb 1/3"; 3/3 -> "bring them to (2,4)"
+	switch {
+	case q.Done:
+		return "done"
+	case q.Kind == "fetch" && q.Have < q.Count:
+		return fmt.Sprintf("%s %d/%d", q.Item, q.Have, q.Count)
+	case q.Kind == "fetch":
+		return fmt.Sprintf("bring them to (%d,%d)", q.GiverX, q.GiverY)
+	}
+	return fmt.Sprintf("go to (%d,%d)", q.X, q.Y)
+}
+
+func questsKey(player string) string {
+	// Redis hash of the quests a player took on, by ID, done ones included
+	// Example: "alice:quests" -> {"2,4#1": {"kind": "visit", ...}}
+	return player + ":quests"
+}
+
+func numberWord(n int) string {
+	// Example: 2 -> "two", 12 -> "12"
+	words := []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+	if n >= 0 && n < len(words) {
+		return words[n]
+	}
+	return strconv.Itoa(n)
+}
+
+func cellsAway(dx, dy int) string {
+	// Example: (2,0) -> "two cells east", (-1,1) -> "one cell northwest"
+	n := max(abs(dx), abs(dy))
+	if n == 1 {
+		return "one cell " + compass(dx, dy)
+	}
+	return numberWord(n) + " cells " + compass(dx, dy)
+}
+
+// questsAt generates the quests offered at (x,y) from what lies nearby
+// The same cell always offers the same quests in the same world
+// Example: (2,4) with ruins at (4,4) and a swamp at (1,6) ->
+// "Find the ancient ruins two cells east", "Bring frog (2) back from the swamp two cells northwest"
+func questsAt(x, y int) []quest {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("quests:%d,%d", x, y)))
+	r := rand.New(rand.NewSource(int64(h.Sum64()) ^ worldSeed))
+
+	// Look around for features to find and places to gather from
+	type spot struct {
+		dx, dy int
+		what   string // Sight's name, or loot table word
+	}
+	var visits, fetches []spot
+	for dy := -questRadius; dy <= questRadius; dy++ {
+		for dx := -questRadius; dx <= questRadius; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			terrain := world.Terrain(x+dx, y+dy)
+			for _, s := range sights {
+				if strings.Contains(terrain, s.word) && max(abs(dx), abs(dy)) >= 2 {
+					visits = append(visits, spot{dx, dy, s.name})
+					break
+				}
+			}
+			if base := strings.Fields(terrain)[0]; base != strings.Fields(world.Terrain(x, y))[0] {
+				fetches = append(fetches, spot{dx, dy, base}) // Something you can't pick up here
+			}
+		}
+	}
+
+	var offers []quest
+	if len(visits) > 0 {
+		s := visits[r.Intn(len(visits))]
+		offers = append(offers, quest{
+			Kind: "visit",
+			Text: fmt.Sprintf("Find %s %s", s.what, cellsAway(s.dx, s.dy)),
+			X:    x + s.dx,
+			Y:    y + s.dy,
+			Reward: []items.Stack{
+				{Name: "old coin", Count: 1 + max(abs(s.dx), abs(s.dy))/2},
+			},
+		})
+	}
+	if len(fetches) > 0 {
+		s := fetches[r.Intn(len(fetches))]
+		for _, table := range items.LootTables {
+			if table.Word != s.what {
+				continue
+			}
+			item, count := table.Items[r.Intn(len(table.Items))], 2+r.Intn(2)
+			reward := []items.Stack{{Name: "old coin", Count: count}}
+			if extra := questRewards[r.Intn(len(questRewards))]; extra != item && r.Intn(2) == 0 {
+				reward = append(reward, items.Stack{Name: extra, Count: 1})
+			}
+			offers = append(offers, quest{
+				Kind:   "fetch",
+				Text:   fmt.Sprintf("Bring %s back from the %s %s", items.Describe([]items.Stack{{Name: item, Count: count}}), s.what, cellsAway(s.dx, s.dy)),
+				X:      x + s.dx,
+				Y:      y + s.dy,
+				Item:   item,
+				Count:  count,
+				Reward: reward,
+			})
+		}
+	}
+	for i := range offers {
+		offers[i].ID = fmt.Sprintf("%d,%d#%d", x, y, i+1)
+		offers[i].GiverX, offers[i].GiverY = x, y
+	}
+	return offers
+}
+
+func readJournal(ctx context.Context, player string) (map[string]quest, error) {
+	// Every quest the player took on, by ID
+	hash, err := rdb.HGetAll(ctx, questsKey(player)).Result()
+	if err != nil {
+		return nil, err
+	}
+	journal := make(map[string]quest, len(hash))
+	for id, data := range hash {
+		var q quest
+		if err := json.Unmarshal([]byte(data), &q); err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable quest", "id", id, "err", err)
+			continue
+		}
+		journal[id] = q
+	}
+	return journal, nil
+}
+
+func sortedQuests(journal map[string]quest) []quest {
+	// Active quests first, each group by ID, so journal numbers stay put
+	list := make([]quest, 0, len(journal))
+	for _, q := range journal {
+		list = append(list, q)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Done != list[j].Done {
+			return !list[i].Done
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// progressQuest counts n more items toward a fetch quest, unless it was
+// finished or abandoned meanwhile; ok is false if it was
+// Example: herb 1/3, picks up 1 -> herb 2/3
+func progressQuest(ctx context.Context, player, id string, n int) (q quest, ok bool, err error) {
+	key := questsKey(player)
+	for try := 0; try < 3; try++ {
+		err = rdb.Watch(ctx, func(tx *redis.Tx) error {
+			ok = false
+			data, err := tx.HGet(ctx, key, id).Result()
+			if err == redis.Nil {
+				return nil // Abandoned
+			} else if err != nil {
+				return err
+			}
+			if err := json.Unmarshal([]byte(data), &q); err != nil || q.Done {
+				return err
+			}
+			q.Have = min(q.Count, q.Have+n)
+			saved, _ := json.Marshal(q)
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, key, id, saved)
+				return nil
+			})
+			ok = err == nil
+			return err
+		}, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return q, ok, err
+		}
+	}
+	return q, false, redis.TxFailedErr
+}
+
+// questEvent is something a player did that may move their quests along
+type questEvent struct {
+	X, Y  int    // Where the player now is
+	Item  string // Picked up, for takes
+	Count int
+}
+
+// advanceQuests updates a player's active quests after a move or take and
+// pays out any that are now done; it returns what to tell the player
+// Example: alice reaches (4,4) -> ["Quest complete: Find the ancient ruins two cells east. You receive old coin (2)"]
+func advanceQuests(ctx context.Context, player string, e questEvent) []string {
+	journal, err := readJournal(ctx, player)
+	if err != nil {
+		slog.WarnContext(ctx, "Reading quests failed", "player", player, "err", err)
+		return nil
+	}
+	var notes []string
+	for _, q := range sortedQuests(journal) {
+		if q.Done {
+			continue
+		}
+		if q.Kind == "fetch" && e.Item == q.Item && q.Have < q.Count {
+			// Progress is worth keeping but not worth failing a move over
+			cur, ok, err := progressQuest(ctx, player, q.ID, e.Count)
+			if err != nil {
+				slog.WarnContext(ctx, "Saving quest progress failed", "player", player, "quest", q.ID, "err", err)
+				continue
+			} else if !ok {
+				continue // Finished or abandoned meanwhile
+			}
+			q = cur
+			if q.Have < q.Count {
+				notes = append(notes, fmt.Sprintf("Quest progress: %s", q.progress()))
+			} else if e.X != q.GiverX || e.Y != q.GiverY {
+				notes = append(notes, fmt.Sprintf("You have enough %s; bring them to (%d,%d)", q.Item, q.GiverX, q.GiverY))
+			}
+		}
+		reached := q.Kind == "visit" && e.X == q.X && e.Y == q.Y
+		delivered := q.Kind == "fetch" && q.Have >= q.Count && e.X == q.GiverX && e.Y == q.GiverY
+		if !reached && !delivered {
+			continue
+		}
+		note, err := completeQuest(ctx, player, q)
+		if err != nil {
+			slog.WarnContext(ctx, "Completing quest failed", "player", player, "quest", q.ID, "err", err)
+			continue
+		}
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// completeQuest marks a quest done and pays its reward, once, taking a
+// fetch quest's items from the inventory in the same transaction
+func completeQuest(ctx context.Context, player string, q quest) (string, error) {
+	key, inv := questsKey(player), inventoryKey(player)
+	var note string
+	for try := 0; try < 3; try++ {
+		err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+			note = ""
+			data, err := tx.HGet(ctx, key, q.ID).Result()
+			if err == redis.Nil {
+				return nil // Abandoned meanwhile
+			} else if err != nil {
+				return err
+			}
+			var cur quest
+			if err := json.Unmarshal([]byte(data), &cur); err != nil || cur.Done {
+				return err // Someone else finished it
+			}
+			have := 0
+			if q.Kind == "fetch" {
+				have, _ = tx.HGet(ctx, inv, q.Item).Int()
+				if have < q.Count {
+					note = fmt.Sprintf("You need %s to finish %q", items.Describe([]items.Stack{{Name: q.Item, Count: q.Count}}), q.Text)
+					cur.Have = have
+					data, _ := json.Marshal(cur)
+					_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+						pipe.HSet(ctx, key, q.ID, data)
+						return nil
+					})
+					return err
+				}
+			}
+			cur.Done = true
+			done, _ := json.Marshal(cur)
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, key, q.ID, done)
+				if q.Kind == "fetch" {
+					if have == q.Count {
+						pipe.HDel(ctx, inv, q.Item)
+					} else {
+						pipe.HIncrBy(ctx, inv, q.Item, -int64(q.Count))
+					}
+				}
+				for _, s := range q.Reward {
+					pipe.HIncrBy(ctx, inv, s.Name, int64(s.Count))
+				}
+				return nil
+			})
+			if err == nil {
+				note = fmt.Sprintf("Quest complete: %s. You receive %s", q.Text, items.Describe(q.Reward))
+				questsCompleted.WithLabelValues(q.Kind).Inc()
+				slog.InfoContext(ctx, "Quest completed", "player", player, "quest", q.ID)
+			}
+			return err
+		}, key, inv)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return note, err
+		}
+	}
+	return "", redis.TxFailedErr
+}
+
+func seeQuests(notes []string) string {
+	// Sentences appended to move and take messages
+	// Example: ["Quest progress: herb 2/3"] -> ". Quest progress: herb 2/3"
+	if len(notes) == 0 {
+		return ""
+	}
+	return ". " + strings.Join(notes, ". ")
+}
+
+// questReply is what /quests, /journal and their actions tell the Client
+type questReply struct {
+	Message string  `json:"message"`
+	Quests  []quest `json:"quests"`
+}
+
+func writeQuestReply(w http.ResponseWriter, r *http.Request, rep questReply) {
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
+
+func describeReward(q quest) string {
+	// Example: "Reward: old coin (2), torch"
+	return "Reward: " + items.Describe(q.Reward)
+}
+
+func questsHandler(w http.ResponseWriter, r *http.Request) {
+	// What's asked of travellers where the player stands
+	// Example: "?player=alice" at (2,4) -> "1. Find the ancient ruins two cells east. Reward: old coin (2)"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if err != nil && err != redis.Nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	journal, err := readJournal(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading quests failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	offers := questsAt(parsePosition(pos))
+	if len(offers) == 0 {
+		writeQuestReply(w, r, questReply{Message: "Nobody here has anything for you to do."})
+		return
+	}
+	lines := make([]string, len(offers))
+	for i, q := range offers {
+		lines[i] = fmt.Sprintf("%d. %s. %s", i+1, q.Text, describeReward(q))
+		if taken, ok := journal[q.ID]; ok {
+			offers[i] = taken // With the player's progress
+			if taken.Done {
+				lines[i] += " (done)"
+			} else {
+				lines[i] += " (taken on)"
+			}
+		}
+	}
+	writeQuestReply(w, r, questReply{Message: strings.Join(lines, "\n"), Quests: offers})
+}
+
+func acceptQuestHandler(w http.ResponseWriter, r *http.Request) {
+	// Take on one of the quests offered where the player stands
+	// Example: "?player=alice&n=1" -> "Quest accepted: Find the ancient ruins two cells east."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	n, err := queryInt(r, "n", 1)
+	if err != nil {
+		http.Error(w, "Bad quest number!", 400)
+		return
+	}
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if err != nil && err != redis.Nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	offers := questsAt(parsePosition(pos))
+	if n < 1 || n > len(offers) {
+		http.Error(w, fmt.Sprintf("There's no quest %d here; see quests.", n), 400)
+		return
+	}
+	q := offers[n-1]
+
+	journal, err := readJournal(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading quests failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	active := 0
+	for _, j := range journal {
+		if !j.Done {
+			active++
+		}
+	}
+	if taken, ok := journal[q.ID]; ok {
+		if taken.Done {
+			http.Error(w, "You've already done that one.", 409)
+		} else {
+			http.Error(w, "You're already on that one; see journal.", 409)
+		}
+		return
+	}
+	if active >= maxActiveQuests {
+		http.Error(w, fmt.Sprintf("You have %d quests on the go already; finish or abandon one first.", active), 400)
+		return
+	}
+	data, _ := json.Marshal(q)
+	added, err := rdb.HSetNX(r.Context(), questsKey(player), q.ID, data).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Saving quest failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if !added {
+		http.Error(w, "You're already on that one; see journal.", 409)
+		return
+	}
+	slog.InfoContext(r.Context(), "Quest accepted", "player", player, "quest", q.ID)
+	writeQuestReply(w, r, questReply{Message: fmt.Sprintf("Quest accepted: %s. %s", q.Text, describeReward(q)), Quests: []quest{q}})
+}
+
+func abandonQuestHandler(w http.ResponseWriter, r *http.Request) {
+	// Give up an active quest; it can be taken on again later
+	// Example: "?player=alice&id=2,4%231" -> "You abandon: Find the ancient ruins two cells east."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	journal, err := readJournal(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading quests failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	q, ok := journal[id]
+	if !ok || q.Done {
+		http.Error(w, "You're not on that quest; see journal.", 400)
+		return
+	}
+	if err := rdb.HDel(r.Context(), questsKey(player), id).Err(); err != nil {
+		slog.ErrorContext(r.Context(), "Abandoning quest failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	writeQuestReply(w, r, questReply{Message: fmt.Sprintf("You abandon: %s.", q.Text)})
+}
+
+func journalHandler(w http.ResponseWriter, r *http.Request) {
+	// The player's quests, active ones first and numbered
+	// Example: "1. Bring herb (3) back from the hill two cells north: herb 1/3"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	journal, err := readJournal(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading quests failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	list := sortedQuests(journal)
+	if len(list) == 0 {
+		writeQuestReply(w, r, questReply{Message: "Your journal is empty. Try: quests"})
+		return
+	}
+	lines := make([]string, len(list))
+	for i, q := range list {
+		lines[i] = fmt.Sprintf("%d. %s (from (%d,%d)): %s", i+1, q.Text, q.GiverX, q.GiverY, q.progress())
+	}
+	writeQuestReply(w, r, questReply{Message: strings.Join(lines, "\n"), Quests: list})
+}
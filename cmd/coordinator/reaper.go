@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/akos011221/driftscape/internal/cluster"
+)
+
+const (
+	// reapInterval is how often the leader reconciles region Deployments
+	reapInterval = 30 * time.Second
+	// reapGrace protects freshly spawned regions a move is still waiting on
+	reapGrace = time.Minute
+)
+
+// runReaper periodically removes regions nobody is standing in and
+// restarts those somebody is standing in that have gone missing
+// Example: A replica died after spawning region-2-4 but before deleting
+// region-2-3; the leader notices region-2-3 is unoccupied and deletes it
+func runReaper(ctx context.Context) {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for {
+		reapRegions(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func reapRegions(ctx context.Context) {
+	// Compare region Deployments against occupied positions in Redis, both ways
+	occupied, err := occupiedPositions(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Reaper could not read positions", "err", err)
+		return
+	}
+	list, err := listRegions(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Reaper could not list regions", "err", err)
+		return
+	}
+
+	live := len(list)
+	defer func() { liveRegions.Set(float64(live)) }()
+
+	running := map[string]bool{}
+	for _, rg := range list {
+		pos := fmt.Sprintf("%d,%d", rg.x, rg.y)
+		running[pos] = true
+		if occupied[pos] || time.Since(rg.created) < reapGrace {
+			continue
+		}
+		slog.InfoContext(ctx, "Reaping unoccupied region", "region", rg.name)
+		deleteRegion(ctx, rg.x, rg.y)
+		live--
+	}
+
+	// The other way round: occupied cells whose region is gone, e.g. a spawn
+	// failed or someone deleted it, get it back before a player trips over it
+	// Checked again first, as a move may have spawned it since the list
+	for pos := range occupied {
+		x, y := parsePosition(pos)
+		if running[pos] || regionExists(ctx, x, y) {
+			continue
+		}
+		slog.InfoContext(ctx, "Respawning missing region", "x", x, "y", y)
+		spawnRegion(ctx, x, y)
+		live++
+	}
+}
+
+// runningRegion is a region the orchestrator has running, pod or process
+type runningRegion struct {
+	name    string
+	x, y    int
+	created time.Time
+}
+
+func listRegions(ctx context.Context) ([]runningRegion, error) {
+	// Every region running now: Deployments, or this process's children locally
+	// Example: Deployment "region-n2-4" labelled x=n2, y=4 -> {-2, 4}
+	if cluster.Local() {
+		return localRegionList(), nil
+	}
+	list, err := clientset.AppsV1().Deployments("default").List(ctx, metav1.ListOptions{
+		LabelSelector: "app=region",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var regions []runningRegion
+	for _, d := range list.Items {
+		x, errX := parseLabel(d.Labels["x"])
+		y, errY := parseLabel(d.Labels["y"])
+		if errX != nil || errY != nil {
+			slog.WarnContext(ctx, "Region has malformed labels", "region", d.Name, "labels", d.Labels)
+			continue
+		}
+		regions = append(regions, runningRegion{name: d.Name, x: x, y: y, created: d.CreationTimestamp.Time})
+	}
+	return regions, nil
+}
+
+func occupiedPositions(ctx context.Context) (map[string]bool, error) {
+	// Positions that must keep their region pod, across all players
+	// A full scan, so only the leader's reaper uses it; moves check occupantsKey
+	// Example: "alice:position" -> "2,4" keeps region-2-4 alive
+	occupied := map[string]bool{}
+	iter := rdb.Scan(ctx, 0, positionKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		pos, err := rdb.Get(ctx, iter.Val()).Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		if pos != "" {
+			occupied[pos] = true
+		}
+	}
+	return occupied, iter.Err()
+}
+
+func parseLabel(label string) (int, error) {
+	// Undo spawnRegion's label sanitizing
+	// Example: "n3" -> -3, "4" -> 4
+	if len(label) > 1 && label[0] == 'n' {
+		n, err := strconv.Atoi(label[1:])
+		return -n, err
+	}
+	return strconv.Atoi(label)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/akos011221/driftscape/internal/items"
+)
+
+// reply is what /look and /move tell the Client
+// Sent as JSON when the request accepts it, otherwise as Message text
+type reply struct {
+	X       int           `json:"x"` // Where the player now is, authoritative
+	Y       int           `json:"y"`
+	Terrain string        `json:"terrain"`
+	Items   []items.Stack `json:"items,omitempty"` // Lying on the ground here
+	NPCs    []npc         `json:"npcs,omitempty"`  // Characters here
+
+	Landmark string `json:"landmark,omitempty"` // The cell's player-given name, e.g. "The Old Ford"
+
+	// Other players: named if they're here, counted by direction if nearby
+	Players []string  `json:"players,omitempty"`
+	Nearby  []figures `json:"nearby,omitempty"`
+
+	// What's happening here, e.g. "the forest is on fire"
+	Conditions []string `json:"conditions,omitempty"`
+	Weather    *sky     `json:"weather,omitempty"`
+
+	Structures []structure `json:"structures,omitempty"` // Built by players, oldest first
+	Resources  []material  `json:"resources,omitempty"`  // What can be gathered here
+
+	Vitals *vitalSigns `json:"vitals,omitempty"` // After a move, what it left the player with
+	Fight  *fightView  `json:"fight,omitempty"`  // Something attacked on arrival
+
+	// Inside a cave or ruins: the room's path and its exits, in order
+	Sublocation string   `json:"sublocation,omitempty"`
+	Exits       []string `json:"exits,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func seeConditions(conditions []string) string {
+	// Sentences appended to look and move messages
+	// Example: ["the forest is on fire"] -> ". The forest is on fire"
+	var b strings.Builder
+	for _, c := range conditions {
+		if c != "" {
+			b.WriteString(". " + strings.ToUpper(c[:1]) + c[1:])
+		}
+	}
+	return b.String()
+}
+
+func wantsJSON(r *http.Request) bool {
+	// Example: "Accept: application/json" -> true, curl's "*/*" -> false
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeReply(w http.ResponseWriter, r *http.Request, rep reply) {
+	// Reply in the format the Client asked for
+	// Example: text "You moved to a plains at (2,4)" or {"x":2,"y":4,...}
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
+
+func writePosition(w http.ResponseWriter, r *http.Request, x, y int, sublocation string) {
+	// Example: text "2,3" or {"x":2,"y":3,"sublocation":"cave/1"}
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		pos := map[string]any{"x": x, "y": y}
+		if sublocation != "" {
+			pos["sublocation"] = sublocation
+		}
+		json.NewEncoder(w).Encode(pos)
+		return
+	}
+	fmt.Fprintf(w, "%d,%d", x, y)
+}
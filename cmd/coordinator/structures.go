@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// adminToken guards /admin/ endpoints (ADMIN_TOKEN); empty disables them
+var adminToken string
+
+// adminTokenHeader carries the token on admin requests
+const adminTokenHeader = "X-Admin-Token"
+
+// structure is a player-built structure as the Client sees it
+type structure struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Builder string `json:"builder"`
+	Text    string `json:"text,omitempty"`
+}
+
+func structures(list []*pb.Structure) []structure {
+	// Convert a region's structures for a reply
+	var out []structure
+	for _, s := range list {
+		out = append(out, structure{ID: s.Id, Kind: s.Kind, Builder: s.Builder, Text: s.Text})
+	}
+	return out
+}
+
+func (s structure) what() string {
+	// Example: `a signpost reading "Ford ahead"`, `a camp`
+	if s.Text != "" {
+		return fmt.Sprintf("a %s reading %q", s.Kind, s.Text)
+	}
+	return "a " + s.Kind
+}
+
+func (s structure) String() string {
+	// With the id admins remove it by
+	// Example: `a signpost reading "Ford ahead" (signpost-1, by bob)`
+	return fmt.Sprintf("%s (%s, by %s)", s.what(), s.ID, s.Builder)
+}
+
+func seeStructures(list []structure) string {
+	// Sentence appended to look and move messages
+	// Example: [camp by alice] -> ". Built here: a camp (camp, by alice)"
+	if len(list) == 0 {
+		return ""
+	}
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = s.String()
+	}
+	return ". Built here: " + strings.Join(names, ", ")
+}
+
+func regionRefusal(err error) (string, bool) {
+	// A region's refusal worded for the player, if err is one
+	// Example: FailedPrecondition "a bridge needs a river" -> "A bridge needs a river.", true
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.NotFound:
+		msg := status.Convert(err).Message()
+		if msg == "" {
+			return "The region refused that.", true
+		}
+		return strings.ToUpper(msg[:1]) + msg[1:] + ".", true
+	}
+	return "", false
+}
+
+func buildHandler(w http.ResponseWriter, r *http.Request) {
+	// Build a structure where the player stands
+	// Example: "?player=alice&kind=signpost&text=Ford ahead" -> signpost at alice's cell
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	kind := strings.ToLower(r.URL.Query().Get("kind"))
+	if kind == "" {
+		http.Error(w, "Build what? (camp, marker, bridge, signpost or road)", 400)
+		return
+	}
+	sub, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if sub != "" {
+		http.Error(w, "There's no room to build in here; exit first!", 400)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var built *pb.Structure
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		built, err = client.Build(ctx, &pb.BuildRequest{
+			Position: &pb.Position{X: int32(x), Y: int32(y)},
+			Kind:     kind,
+			Builder:  player,
+			Text:     r.URL.Query().Get("text"),
+		})
+		return err
+	})
+	if msg, ok := regionRefusal(err); ok {
+		http.Error(w, msg, 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region unreachable", "region", podName, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+	st := structures([]*pb.Structure{built})[0]
+	writeReply(w, r, reply{X: x, Y: y, Terrain: storedTerrain(r.Context(), x, y), Structures: []structure{st},
+		Message: fmt.Sprintf("You build %s here.", st.what())})
+}
+
+func adminRemoveHandler(w http.ResponseWriter, r *http.Request) {
+	// Take down a structure anywhere, for admins
+	// Example: "?x=2&y=4&id=signpost-1" with X-Admin-Token -> removed
+	if adminToken == "" {
+		http.Error(w, "Admin commands are disabled", 403)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(adminTokenHeader)), []byte(adminToken)) != 1 {
+		http.Error(w, "Bad admin token!", 403)
+		return
+	}
+	x, y, err := getXY(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "Remove what? Give the structure's id", 400)
+		return
+	}
+	if !regionExists(r.Context(), x, y) {
+		spawnRegion(r.Context(), x, y) // The reaper takes it down again later
+	}
+
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var removed *pb.Structure
+	err = callRegion(r.Context(), podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		removed, err = client.RemoveStructure(ctx, &pb.RemoveRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Id: id})
+		return err
+	})
+	if msg, ok := regionRefusal(err); ok {
+		http.Error(w, msg, 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region unreachable", "region", podName, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+	slog.InfoContext(r.Context(), "Structure removed by admin", "x", x, "y", y, "id", id, "builder", removed.Builder)
+	writeReply(w, r, reply{X: x, Y: y, Terrain: storedTerrain(r.Context(), x, y),
+		Message: fmt.Sprintf("Removed %s at (%d,%d).", structures([]*pb.Structure{removed})[0], x, y)})
+}
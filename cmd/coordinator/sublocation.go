@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// errNoRoom means the region has no such room (or nothing to enter at all)
+var errNoRoom = errors.New("no such room")
+
+func sublocationKey(player string) string {
+	// Redis key holding the room a player is in, if they're inside a feature
+	// Example: "alice:sublocation" -> "cave/2/1"; missing means outside
+	return player + ":sublocation"
+}
+
+func getSublocation(ctx context.Context, player string) (string, error) {
+	// Example: "alice" in the cave's second room -> "cave/2", outside -> ""
+	sub, err := rdb.Get(ctx, sublocationKey(player)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return sub, err
+}
+
+func getRoom(ctx context.Context, x, y int, path string) (*pb.Room, error) {
+	// Ask the owning region pod for a room of its interior
+	// Example: (2,4) "cave/2" -> "crystal grotto" with its exits
+	podName := fmt.Sprintf("region-%d-%d", x, y)
+	var room *pb.Room
+	err := callRegion(ctx, podName, func(ctx context.Context, client pb.RegionServiceClient) error {
+		var err error
+		room, err = client.GetRoom(ctx, &pb.RoomRequest{Position: &pb.Position{X: int32(x), Y: int32(y)}, Path: path})
+		return err
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, errNoRoom
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get room from %s: %w", podName, err)
+	}
+	return room, nil
+}
+
+func roomReply(x, y int, terrain string, room *pb.Room) reply {
+	// Describe a room and its ways on
+	// Example: "You're in a cave mouth. ... Exits: 1) narrow tunnel, 2) bat roost. Type exit to go back."
+	var exits []string
+	var listed []string
+	for i, e := range room.Exits {
+		exits = append(exits, e.Name)
+		listed = append(listed, fmt.Sprintf("%d) %s", i+1, e.Name))
+	}
+	msg := room.Description
+	if len(listed) > 0 {
+		msg += " Exits: " + strings.Join(listed, ", ") + "."
+	} else {
+		msg += " There's no way further in."
+	}
+	msg += " Type exit to go back."
+	return reply{X: x, Y: y, Terrain: terrain, Sublocation: room.Path, Exits: exits, Message: msg}
+}
+
+func findExit(room *pb.Room, to string) (*pb.Exit, bool) {
+	// Match an exit by number or by name
+	// Example: "2" -> second exit, "crystal" -> the "crystal grotto"
+	if n, err := strconv.Atoi(to); err == nil {
+		if n >= 1 && n <= len(room.Exits) {
+			return room.Exits[n-1], true
+		}
+		return nil, false
+	}
+	to = strings.ToLower(to)
+	for _, e := range room.Exits {
+		if strings.HasPrefix(e.Name, to) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+func storedTerrain(ctx context.Context, x, y int) string {
+	// The terrain last saved for a cell, for replies that don't ask the region
+	terrain, err := rdb.Get(ctx, fmt.Sprintf("region:%d,%d", x, y)).Result()
+	if err != nil && err != redis.Nil {
+		slog.WarnContext(ctx, "Reading region failed", "x", x, "y", y, "err", err)
+	}
+	return terrain
+}
+
+func enterHandler(w http.ResponseWriter, r *http.Request) {
+	// Enter the cave or ruins where the player stands, or go deeper inside
+	// Example: "?player=alice" -> cave mouth, then "?player=alice&to=2" -> its second exit
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	// A trip would carry the player off with the room left behind, and
+	// nobody slips away from a fight
+	travelling, err := rdb.Exists(r.Context(), travelKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading travel failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if travelling > 0 {
+		http.Error(w, "You're travelling; cancel that first.", 409)
+		return
+	}
+	if at, err := fighting(r.Context(), player); err != nil {
+		slog.ErrorContext(r.Context(), "Reading fight failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	} else if at != "" {
+		http.Error(w, "You're in a fight! Attack, flee or use an item.", 409)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	current, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+
+	// Outside, entering means the entrance; inside, follow one of the room's exits
+	target := ""
+	if current != "" {
+		to := r.URL.Query().Get("to")
+		if to == "" {
+			http.Error(w, "You're already inside. Enter which way? (e.g. enter 1)", 400)
+			return
+		}
+		room, err := getRoom(r.Context(), x, y, current)
+		if err != nil {
+			slog.WarnContext(r.Context(), "Region unreachable", "x", x, "y", y, "err", err)
+			http.Error(w, "The region isn't answering, try again", 503)
+			return
+		}
+		exit, ok := findExit(room, to)
+		if !ok {
+			http.Error(w, fmt.Sprintf("There's no way to %q from here.", to), 400)
+			return
+		}
+		target = exit.Path
+	}
+
+	room, err := getRoom(r.Context(), x, y, target)
+	if err == errNoRoom {
+		http.Error(w, "There's nothing to enter here.", 400)
+		return
+	} else if err != nil {
+		slog.WarnContext(r.Context(), "Region unreachable", "x", x, "y", y, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+	if err := rdb.Set(r.Context(), sublocationKey(player), room.Path, 0).Err(); err != nil {
+		slog.ErrorContext(r.Context(), "Saving sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	writeReply(w, r, roomReply(x, y, storedTerrain(r.Context(), x, y), room))
+}
+
+func exitHandler(w http.ResponseWriter, r *http.Request) {
+	// Go back one room, or outside from the entrance
+	// Example: "cave/2" -> "cave" (the mouth), "cave" -> outside at (x,y)
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	x, y, err := playerRegion(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	current, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if current == "" {
+		http.Error(w, "You're not inside anything.", 400)
+		return
+	}
+
+	terrain := storedTerrain(r.Context(), x, y)
+	i := strings.LastIndex(current, "/")
+	if i < 0 {
+		if err := rdb.Del(r.Context(), sublocationKey(player)).Err(); err != nil {
+			slog.ErrorContext(r.Context(), "Clearing sublocation failed", "err", err)
+			http.Error(w, "Redis error", 500)
+			return
+		}
+		writeReply(w, r, reply{X: x, Y: y, Terrain: terrain,
+			Message: fmt.Sprintf("You step back outside into the %s at (%d,%d)", terrain, x, y)})
+		return
+	}
+
+	room, err := getRoom(r.Context(), x, y, current[:i])
+	if err != nil {
+		slog.WarnContext(r.Context(), "Region unreachable", "x", x, "y", y, "err", err)
+		http.Error(w, "The region isn't answering, try again", 503)
+		return
+	}
+	if err := rdb.Set(r.Context(), sublocationKey(player), room.Path, 0).Err(); err != nil {
+		slog.ErrorContext(r.Context(), "Saving sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	writeReply(w, r, roomReply(x, y, terrain, room))
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/akos011221/driftscape/internal/world"
+)
+
+const (
+	maxTravelSteps = 50    // Longest route one /travel will walk
+	searchLimit    = 20000 // Cells A* may explore before giving up
+
+	// travelStepDelay paces auto-travel so other players see you pass
+	travelStepDelay = 500 * time.Millisecond
+
+	// travelLockTTL frees a player's travel lock if a replica dies mid-trip
+	travelLockTTL = 30 * time.Second
+)
+
+// worldSeed must match the regions' so planned weather is what players meet (WORLD_SEED)
+var worldSeed int64
+
+var travels = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "driftscape_travels_total",
+	Help: "Auto-travel trips started on this replica, by how they ended.",
+}, []string{"result"})
+
+// travelStep is sent as each cell on the route is reached
+type travelStep struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Terrain string `json:"terrain"`
+	Step    int    `json:"step"`              // 1-based
+	Steps   int    `json:"steps"`             // Route length
+	Stamina int    `json:"stamina"`           // Left after the step
+	Message string `json:"message,omitempty"` // e.g. a quest completed on the way
+}
+
+// travelStop is sent when a trip ends early, with where the player is left
+type travelStop struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Reason string `json:"reason"`
+}
+
+func travelKey(player string) string {
+	// Redis key held while a player is auto-travelling
+	// Example: "alice:travelling" -> random token of the trip, expires after 30s
+	return player + ":travelling"
+}
+
+// releaseTravel deletes a travel lock only if it's still this trip's
+var releaseTravel = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func blockedAt(ctx context.Context, p world.Point, now world.Time, past map[string]bool) string {
+	// Why the weather bars entering p right now, or "" if it doesn't
+	// past are the hazards the player's tools get them past (see hazardsPast)
+	// Example: (3,1) "plains with a river" in heavy rain, no bridge or boat -> "The river is too swollen..."
+	terrain := world.Terrain(p.X, p.Y)
+	w := world.WeatherAt(worldSeed, p.X, p.Y, now)
+	if world.Blocked(terrain, w, false) == "" {
+		return "" // Only ask Redis about bridges where they'd matter
+	}
+	return world.BlockedPast(terrain, w, func(hazard string) bool {
+		return past[hazard] || hazard == "river" && hasStructure(ctx, p.X, p.Y, "bridge")
+	})
+}
+
+// planRoute finds the easiest route between two cells as the weather stands
+// Regions aren't asked: terrain is generated and weather computed on the spot;
+// roads aren't looked up, since that would mean a Redis call per cell explored
+// Example: (0,0) to (3,1) -> [(1,0) (2,1) (3,1)], around a stormy hill
+func planRoute(ctx context.Context, from, to world.Point, now world.Time, past map[string]bool) ([]world.Point, bool) {
+	return world.FindPath(from, to, func(p world.Point) (int, bool) {
+		if blockedAt(ctx, p, now, past) != "" {
+			return 0, false
+		}
+		return world.MoveCost(world.Terrain(p.X, p.Y)), true
+	}, searchLimit)
+}
+
+func travelTarget(ctx context.Context, r *http.Request) (world.Point, string, error) {
+	// Where to travel: a landmark by name or coordinates
+	// Example: "?landmark=the old ford" -> (14,-3); "?x=2&y=4" -> (2,4)
+	if name := r.URL.Query().Get("landmark"); name != "" {
+		data, err := rdb.HGet(ctx, landmarksKey, normalizeName(name)).Result()
+		if err == redis.Nil {
+			return world.Point{}, "", fmt.Errorf("No landmark called %q; check the gazetteer", name)
+		} else if err != nil {
+			return world.Point{}, "", err
+		}
+		var l landmark
+		if err := json.Unmarshal([]byte(data), &l); err != nil {
+			return world.Point{}, "", err
+		}
+		return world.Point{X: l.X, Y: l.Y}, l.Name, nil
+	}
+	x, y, err := getXY(r)
+	if err != nil {
+		return world.Point{}, "", err
+	}
+	return world.Point{X: x, Y: y}, fmt.Sprintf("(%d,%d)", x, y), nil
+}
+
+func travelHandler(w http.ResponseWriter, r *http.Request) {
+	// Walk the player to a far cell, streaming each step as Server-Sent Events
+	// Example: "?player=alice&x=5&y=2" -> "event: step" x5, then "event: done"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	to, where, err := travelTarget(r.Context(), r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	sub, err := getSublocation(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading sublocation failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if sub != "" {
+		http.Error(w, "You can't travel from in here; exit first!", 400)
+		return
+	}
+	if at, err := fighting(r.Context(), player); err != nil {
+		slog.ErrorContext(r.Context(), "Reading fight failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	} else if at != "" {
+		http.Error(w, "You're in a fight! Attack, flee or use an item.", 400)
+		return
+	}
+	pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+	if err != nil && err != redis.Nil {
+		slog.ErrorContext(r.Context(), "Reading position failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	fromX, fromY := parsePosition(pos)
+	from := world.Point{X: fromX, Y: fromY}
+	if from == to {
+		http.Error(w, "You're already there.", 400)
+		return
+	}
+	if max(abs(to.X-from.X), abs(to.Y-from.Y)) > maxTravelSteps {
+		http.Error(w, fmt.Sprintf("That's too far to travel in one go; %d cells at most.", maxTravelSteps), 400)
+		return
+	}
+
+	now, err := world.ReadClock(r.Context(), rdb)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading world clock failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	past := hazardsPast(readInventory(r.Context(), player)) // Tools carried when setting out
+	route, ok := planRoute(r.Context(), from, to, now, past)
+	if !ok || len(route) > maxTravelSteps {
+		http.Error(w, fmt.Sprintf("There's no way to %s from here in this weather.", where), 400)
+		return
+	}
+
+	// One trip per player; /travel/cancel deletes the lock to stop it
+	// Example: "alice:travelling" -> "9f2c..." until she arrives
+	token := make([]byte, 8)
+	rand.Read(token)
+	trip := hex.EncodeToString(token)
+	started, err := rdb.SetNX(r.Context(), travelKey(player), trip, travelLockTTL).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Locking travel failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if !started {
+		http.Error(w, "You're already travelling; cancel that first.", 409)
+		return
+	}
+	defer releaseTravel.Run(context.WithoutCancel(r.Context()), rdb, []string{travelKey(player)}, trip)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		slog.ErrorContext(r.Context(), "Streaming unsupported", "err", err)
+		return
+	}
+	send := func(event string, v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		rc.Flush()
+	}
+	stop := func(result string, at world.Point, reason string) {
+		travels.WithLabelValues(result).Inc()
+		slog.InfoContext(r.Context(), "Travel stopped", "player", player, "x", at.X, "y", at.Y, "reason", reason)
+		send("stopped", travelStop{X: at.X, Y: at.Y, Reason: reason})
+	}
+	slog.InfoContext(r.Context(), "Travel started", "player", player, "to", where, "steps", len(route))
+
+	at := from
+	for i, next := range route {
+		if i > 0 {
+			select {
+			case <-r.Context().Done():
+				travels.WithLabelValues("disconnected").Inc()
+				return // The player stays wherever the last step left them
+			case <-closingStreams:
+				stop("interrupted", at, "The road ahead fades; travel again to carry on.")
+				return
+			case <-time.After(travelStepDelay):
+			}
+		}
+
+		// Stop if cancelled, or if the lock lapsed and someone else holds it
+		if held, err := rdb.Get(r.Context(), travelKey(player)).Result(); err != nil || held != trip {
+			stop("cancelled", at, "You stop travelling.")
+			return
+		}
+		rdb.Expire(r.Context(), travelKey(player), travelLockTTL)
+
+		// Weather moves on while you walk, so check each step as you reach it
+		if now, err := world.ReadClock(r.Context(), rdb); err == nil {
+			if why := blockedAt(r.Context(), next, now, past); why != "" {
+				stop("blocked", at, why)
+				return
+			}
+		}
+
+		// The last step is a real move: its region is spawned and described
+		if i == len(route)-1 {
+			rep, merr := doMove(r.Context(), player, next.X, next.Y)
+			if merr != nil {
+				stop("blocked", at, merr.msg)
+				return
+			}
+			travels.WithLabelValues("done").Inc()
+			rep.Message = fmt.Sprintf("You arrive at %s after %d steps. ", where, len(route)) + rep.Message
+			send("done", rep)
+			return
+		}
+
+		// Cells on the way are passed through without starting their pods,
+		// as long as the player is still where this trip left them: not moved,
+		// gone inside or caught in a fight by another request
+		// Example: alice walks over (1,0) -> position, presence and map updated
+		pos, err := rdb.Get(r.Context(), positionKey(player)).Result()
+		if err != nil && err != redis.Nil {
+			stop("error", at, "Redis error")
+			return
+		}
+		if curX, curY := parsePosition(pos); curX != at.X || curY != at.Y {
+			stop("cancelled", world.Point{X: curX, Y: curY}, "You went your own way.")
+			return
+		}
+		if sub, err := getSublocation(r.Context(), player); err != nil {
+			stop("error", at, "Redis error")
+			return
+		} else if sub != "" {
+			stop("cancelled", at, "You went inside; travel stops here.")
+			return
+		}
+		if fight, err := fighting(r.Context(), player); err != nil {
+			stop("error", at, "Redis error")
+			return
+		} else if fight != "" {
+			stop("cancelled", at, "You're in a fight! Attack, flee or use an item.")
+			return
+		}
+		terrain := world.Terrain(next.X, next.Y)
+		v, why, err := spendStamina(r.Context(), player, terrain, moveStamina(r.Context(), next.X, next.Y, terrain))
+		if err != nil {
+			stop("error", at, "Redis error")
+			return
+		} else if why != "" {
+			stop("exhausted", at, why)
+			return
+		}
+		if merr := relocate(r.Context(), player, pos, next.X, next.Y); merr != nil {
+			stop("error", at, merr.msg)
+			return
+		}
+		recordDiscovery(r.Context(), player, next.X, next.Y, terrain)
+		at = next
+		notes := advanceQuests(r.Context(), player, questEvent{X: next.X, Y: next.Y})
+		send("step", travelStep{X: next.X, Y: next.Y, Terrain: terrain, Step: i + 1, Steps: len(route), Stamina: int(v.Stamina),
+			Message: strings.Join(notes, ". ")})
+	}
+}
+
+func cancelTravelHandler(w http.ResponseWriter, r *http.Request) {
+	// Stop the player's trip; it ends at the next step
+	// Example: "?player=alice" -> alice's /travel stream sends "event: stopped"
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	n, err := rdb.Del(r.Context(), travelKey(player)).Result()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Cancelling travel failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "You aren't travelling.", 400)
+		return
+	}
+	fmt.Fprint(w, "You'll stop at the next step.")
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/akos011221/driftscape/internal/items"
+	"github.com/akos011221/driftscape/internal/world"
+)
+
+const (
+	maxHealth  = 30.0 // Creatures take it; at 0 the player wakes up at home
+	maxStamina = 100.0
+	maxHunger  = 100.0 // Starving
+
+	staminaPerCost = 5.0 // Stamina a move takes per point of world.MoveCost
+	staminaRegen   = 0.5 // Stamina recovered per world minute
+	healthRegen    = 0.1 // Health recovered per world minute
+	restFactor     = 3.0 // Resting recovers this many times faster
+	hungerRate     = 0.1 // Hunger gained per world minute, so a world day starves you
+
+	// hungry halves how fast stamina comes back; starving stops it
+	hungry = 75.0
+)
+
+// foods are what can be eaten, and how much hunger each takes away
+// Eating also gives back half that in stamina
+var foods = map[string]float64{
+	"fish":     35,
+	"mushroom": 20,
+	"frog":     15,
+	"herb":     10,
+	"pinecone": 5,
+}
+
+// vitals are a player's health, stamina and hunger as of world time At
+// Stored in the "<player>:vitals" hash and settled forward whenever read
+type vitals struct {
+	Health  float64
+	Stamina float64
+	Hunger  float64
+	Resting bool
+	At      world.Time
+}
+
+// vitalSigns are vitals as the Client sees them
+type vitalSigns struct {
+	Health  int    `json:"health"`  // Out of 30
+	Stamina int    `json:"stamina"` // Out of 100
+	Hunger  int    `json:"hunger"`  // Out of 100; 100 is starving
+	Resting bool   `json:"resting"`
+	Feeling string `json:"feeling"` // e.g. "peckish"
+}
+
+func vitalsKey(player string) string {
+	// Redis hash of a player's vitals
+	// Example: "alice:vitals" -> {"health": "24", "stamina": "62.5", "hunger": "20", "resting": "0", "at": "1820"}
+	return player + ":vitals"
+}
+
+// since settles vitals forward to now: health and stamina come back, hunger grows
+// Example: 40 stamina resting for 10 world minutes -> 55
+func (v vitals) since(now world.Time) vitals {
+	mins := float64(now - v.At)
+	if mins <= 0 {
+		return v
+	}
+	rate := 1.0
+	if v.Resting {
+		rate *= restFactor
+	}
+	switch {
+	case v.Hunger >= maxHunger:
+		rate = 0
+	case v.Hunger >= hungry:
+		rate /= 2
+	}
+	v.Health = min(maxHealth, v.Health+healthRegen*rate*mins)
+	v.Stamina = min(maxStamina, v.Stamina+staminaRegen*rate*mins)
+	v.Hunger = min(maxHunger, v.Hunger+hungerRate*mins)
+	v.At = now
+	return v
+}
+
+func (v vitals) signs() *vitalSigns {
+	return &vitalSigns{Health: int(math.Ceil(v.Health)), Stamina: int(v.Stamina), Hunger: int(v.Hunger), Resting: v.Resting, Feeling: v.feeling()}
+}
+
+func (v vitals) feeling() string {
+	// Example: hunger 60 -> "hungry"
+	switch {
+	case v.Hunger >= maxHunger:
+		return "starving"
+	case v.Hunger >= hungry:
+		return "very hungry"
+	case v.Hunger >= 50:
+		return "hungry"
+	case v.Hunger >= 25:
+		return "peckish"
+	}
+	return "fed"
+}
+
+func (v vitals) String() string {
+	// Example: "Health 24/30, stamina 62/100, hunger 20/100 (fed). You're resting."
+	s := fmt.Sprintf("Health %d/%d, stamina %d/%d, hunger %d/%d (%s).", int(math.Ceil(v.Health)), int(maxHealth),
+		int(v.Stamina), int(maxStamina), int(v.Hunger), int(maxHunger), v.feeling())
+	switch {
+	case v.Hunger >= maxHunger:
+		s += " You're too hungry to recover; eat something!"
+	case v.Hunger >= hungry:
+		s += " Hunger slows your recovery."
+	}
+	if v.Resting {
+		s += " You're resting."
+	}
+	return s
+}
+
+func seeVitals(v vitals) string {
+	// Warning appended to move messages when it's time to stop
+	// Example: 12 stamina -> ". You're getting tired"
+	var warnings []string
+	if v.Stamina < 25 {
+		warnings = append(warnings, "You're getting tired")
+	}
+	if v.Hunger >= hungry {
+		warnings = append(warnings, "Your stomach growls")
+	}
+	if len(warnings) == 0 {
+		return ""
+	}
+	return ". " + strings.Join(warnings, ". ")
+}
+
+// readVitals loads a player's vitals settled to the current world time
+// New players start healthy, rested and fed
+func readVitals(ctx context.Context, player string) (vitals, error) {
+	return loadVitals(ctx, rdb, player)
+}
+
+func loadVitals(ctx context.Context, c redis.Cmdable, player string) (vitals, error) {
+	now, err := world.ReadClock(ctx, c)
+	if err != nil {
+		return vitals{}, err
+	}
+	hash, err := c.HGetAll(ctx, vitalsKey(player)).Result()
+	if err != nil {
+		return vitals{}, err
+	}
+	if len(hash) == 0 {
+		return vitals{Health: maxHealth, Stamina: maxStamina, At: now}, nil
+	}
+	health := maxHealth // Kept before there was fighting
+	if h, ok := hash["health"]; ok {
+		health, _ = strconv.ParseFloat(h, 64)
+	}
+	stamina, _ := strconv.ParseFloat(hash["stamina"], 64)
+	hunger, _ := strconv.ParseFloat(hash["hunger"], 64)
+	at, _ := strconv.ParseInt(hash["at"], 10, 64)
+	v := vitals{Health: health, Stamina: stamina, Hunger: hunger, Resting: hash["resting"] == "1", At: world.Time(at)}
+	return v.since(now), nil
+}
+
+// updateVitals changes a player's vitals unless change refuses, returning
+// the refusal; concurrent updates (say, travel and eat) retry on a fresh read
+func updateVitals(ctx context.Context, player string, change func(*vitals) string) (vitals, string, error) {
+	var v vitals
+	var why string
+	key := vitalsKey(player)
+	for try := 0; try < 3; try++ {
+		err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+			var err error
+			if v, err = loadVitals(ctx, tx, player); err != nil {
+				return err
+			}
+			if why = change(&v); why != "" {
+				return nil
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				resting := "0"
+				if v.Resting {
+					resting = "1"
+				}
+				pipe.HSet(ctx, key, "health", v.Health, "stamina", v.Stamina, "hunger", v.Hunger, "resting", resting, "at", int64(v.At))
+				return nil
+			})
+			return err
+		}, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return v, why, err
+		}
+	}
+	return v, "", redis.TxFailedErr
+}
+
+func hasStructure(ctx context.Context, x, y int, kind string) bool {
+	// Whether players have built a unique structure like a bridge or road at (x,y)
+	ok, err := rdb.HExists(ctx, fmt.Sprintf("region:%d,%d:structures", x, y), kind).Result()
+	if err != nil {
+		slog.WarnContext(ctx, "Reading structures failed", "x", x, "y", y, "err", err)
+	}
+	return ok
+}
+
+func moveStamina(ctx context.Context, x, y int, terrain string) float64 {
+	// Stamina it takes to walk into (x,y); a road makes it easier
+	// Example: "swamp with a river" -> 20, or 10 along a road
+	if hasStructure(ctx, x, y, "road") {
+		return staminaPerCost * float64(world.RoadCost(terrain))
+	}
+	return staminaPerCost * float64(world.MoveCost(terrain))
+}
+
+func exhausted(v vitals, terrain string, cost float64) string {
+	// Why a player can't afford a move, or "" if they can
+	// Example: 3 stamina into a forest -> "You're too exhausted to go on: ..."
+	if v.Stamina >= cost {
+		return ""
+	}
+	return fmt.Sprintf("You're too exhausted to go on: the %s takes %d stamina and you have %d. Rest or eat first.",
+		strings.Fields(terrain)[0], int(cost), int(v.Stamina))
+}
+
+func spendStamina(ctx context.Context, player, terrain string, cost float64) (vitals, string, error) {
+	// Pay for a move; moving on also ends a rest
+	return updateVitals(ctx, player, func(v *vitals) string {
+		if why := exhausted(*v, terrain, cost); why != "" {
+			return why
+		}
+		v.Stamina -= cost
+		v.Resting = false
+		return ""
+	})
+}
+
+// vitalsReply is what /vitals, /rest and /eat tell the Client
+type vitalsReply struct {
+	Message   string        `json:"message"`
+	Vitals    *vitalSigns   `json:"vitals"`
+	Inventory []items.Stack `json:"inventory,omitempty"` // After eating
+}
+
+func writeVitalsReply(w http.ResponseWriter, r *http.Request, rep vitalsReply) {
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rep)
+		return
+	}
+	fmt.Fprint(w, rep.Message)
+}
+
+func vitalsHandler(w http.ResponseWriter, r *http.Request) {
+	// How the player is doing
+	// Example: "?player=alice" -> "Stamina 62/100, hunger 20/100 (fed)."
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	v, err := readVitals(r.Context(), player)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Reading vitals failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	writeVitalsReply(w, r, vitalsReply{Message: v.String(), Vitals: v.signs()})
+}
+
+func restHandler(w http.ResponseWriter, r *http.Request) {
+	// Sit down to recover faster until the next move
+	// Example: "?player=alice" -> stamina comes back three times as fast
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	v, why, err := updateVitals(r.Context(), player, func(v *vitals) string {
+		switch {
+		case v.Resting:
+			return "You're already resting."
+		case v.Stamina >= maxStamina && v.Health >= maxHealth:
+			return "You're already fully rested."
+		}
+		v.Resting = true
+		return ""
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Updating vitals failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if why != "" {
+		http.Error(w, why, 400)
+		return
+	}
+	msg := "You sit down to rest; you'll recover faster until you move on. " + v.String()
+	if v.Hunger >= maxHunger {
+		msg = "You sit down, but you're too hungry to rest well. " + v.String()
+	}
+	writeVitalsReply(w, r, vitalsReply{Message: msg, Vitals: v.signs()})
+}
+
+func eatHandler(w http.ResponseWriter, r *http.Request) {
+	// Eat one of something from the inventory
+	// Example: "?player=alice&item=fish" -> hunger 60 -> 25
+	player, err := getPlayer(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	name := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("item")))
+	food, edible := foods[name]
+	if !edible {
+		if !items.ValidName(name) {
+			http.Error(w, "Eat what?", 400)
+			return
+		}
+		http.Error(w, fmt.Sprintf("You can't eat %s!", name), 400)
+		return
+	}
+
+	ok, err := items.Take(r.Context(), rdb, inventoryKey(player), name, 1)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Taking from inventory failed", "err", err)
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("You don't have any %s.", name), 400)
+		return
+	}
+	v, _, err := updateVitals(r.Context(), player, func(v *vitals) string {
+		v.Hunger = max(0, v.Hunger-food)
+		v.Stamina = min(maxStamina, v.Stamina+food/2)
+		return ""
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Updating vitals failed", "err", err)
+		rdb.HIncrBy(r.Context(), inventoryKey(player), name, 1) // Put it back uneaten
+		http.Error(w, "Redis error", 500)
+		return
+	}
+	writeVitalsReply(w, r, vitalsReply{Message: fmt.Sprintf("You eat the %s. %s", name, v), Vitals: v.signs(),
+		Inventory: readInventory(r.Context(), player)})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/akos011221/driftscape/internal/world"
+)
+
+func TestVitalsSince(t *testing.T) {
+	tests := []struct {
+		name string
+		v    vitals
+		now  world.Time
+		want vitals
+	}{
+		{
+			name: "no time passed",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: 10, At: 100},
+			now:  100,
+			want: vitals{Health: 20, Stamina: 40, Hunger: 10, At: 100},
+		},
+		{
+			name: "clock behind",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: 10, At: 100},
+			now:  90,
+			want: vitals{Health: 20, Stamina: 40, Hunger: 10, At: 100},
+		},
+		{
+			name: "walking about",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: 10, At: 100},
+			now:  110,
+			want: vitals{Health: 21, Stamina: 45, Hunger: 11, At: 110},
+		},
+		{
+			name: "resting",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: 10, Resting: true, At: 100},
+			now:  110,
+			want: vitals{Health: 23, Stamina: 55, Hunger: 11, Resting: true, At: 110},
+		},
+		{
+			name: "hungry halves recovery",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: hungry, At: 100},
+			now:  110,
+			want: vitals{Health: 20.5, Stamina: 42.5, Hunger: hungry + 1, At: 110},
+		},
+		{
+			name: "starving stops recovery",
+			v:    vitals{Health: 20, Stamina: 40, Hunger: maxHunger, Resting: true, At: 100},
+			now:  110,
+			want: vitals{Health: 20, Stamina: 40, Hunger: maxHunger, Resting: true, At: 110},
+		},
+		{
+			name: "capped",
+			v:    vitals{Health: maxHealth - 1, Stamina: maxStamina - 1, Hunger: maxHunger - 1, At: 0},
+			now:  1000,
+			want: vitals{Health: maxHealth, Stamina: maxStamina, Hunger: maxHunger, At: 1000},
+		},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.v.since(tt.now)
+			if !near(got.Health, tt.want.Health) || !near(got.Stamina, tt.want.Stamina) || !near(got.Hunger, tt.want.Hunger) ||
+				got.Resting != tt.want.Resting || got.At != tt.want.At {
+				t.Errorf("since(%d) = %+v, want %+v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
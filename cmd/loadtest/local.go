@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// localStartTimeout bounds the wait for a local Coordinator to answer /healthz
+const localStartTimeout = 15 * time.Second
+
+// startLocal runs a Coordinator on this machine with ORCHESTRATOR=local, so
+// it starts regions as its own child processes instead of Kubernetes pods;
+// only Redis has to be running already. Its logs and its regions' go to a
+// temporary file. Returns its URL and a function that stops it and its regions.
+// Example: ("coordinator", "region", "localhost:6379") -> "http://127.0.0.1:40123"
+func startLocal(coordinatorBin, regionBin, redisAddr string) (string, func(), error) {
+	coordinatorPath, err := exec.LookPath(coordinatorBin)
+	if err != nil {
+		return "", nil, fmt.Errorf("coordinator binary: %w", err)
+	}
+	regionPath, err := exec.LookPath(regionBin)
+	if err != nil {
+		return "", nil, fmt.Errorf("region binary: %w", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	listen := l.Addr().String()
+	l.Close()
+	logs, err := os.CreateTemp("", "driftscape-local-*.log")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cmd := exec.Command(coordinatorPath)
+	cmd.Env = append(os.Environ(),
+		"ORCHESTRATOR=local",
+		"REDIS_ADDR="+redisAddr,
+		"REGION_BIN="+regionPath,
+		"LISTEN_ADDR="+listen,
+	)
+	cmd.Stdout, cmd.Stderr = logs, logs
+	if err := cmd.Start(); err != nil {
+		logs.Close()
+		return "", nil, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	stop := func() {
+		// The Coordinator drains, then stops every region it started
+		cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(time.Minute):
+			cmd.Process.Kill()
+		}
+		logs.Close()
+	}
+	fmt.Printf("Started a local coordinator on %s (logs in %s)\n", listen, logs.Name())
+
+	url := "http://" + listen
+	deadline := time.Now().Add(localStartTimeout)
+	for time.Now().Before(deadline) {
+		if resp, err := httpClient.Get(url + "/healthz"); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return url, stop, nil
+			}
+		}
+		select {
+		case <-exited:
+			logs.Close()
+			return "", nil, fmt.Errorf("local coordinator exited on start; see %s", logs.Name())
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	stop()
+	return "", nil, fmt.Errorf("local coordinator didn't become healthy in %s; see %s", localStartTimeout, logs.Name())
+}
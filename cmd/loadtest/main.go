@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akos011221/driftscape/internal/coordclient"
+)
+
+// sample is one timed request made by a simulated player
+type sample struct {
+	op      string // "move", "look", "rest" or "fight"
+	latency time.Duration
+	failed  bool
+	refused bool // A 4xx: the Coordinator answered but said no, e.g. too exhausted to move
+}
+
+// recorder collects samples from all players
+type recorder struct {
+	mu      sync.Mutex
+	samples []sample
+}
+
+func (r *recorder) add(s sample) {
+	r.mu.Lock()
+	r.samples = append(r.samples, s)
+	r.mu.Unlock()
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second} // For /metrics
+
+func main() {
+	addr := flag.String("addr", os.Getenv("COORDINATOR_ADDR"), "coordinator URL (default $COORDINATOR_ADDR or http://localhost:8080)")
+	players := flag.Int("players", 10, "simulated players running at once")
+	duration := flag.Duration("duration", 30*time.Second, "how long to run")
+	lookRatio := flag.Float64("look", 0.3, "fraction of actions that are looks instead of moves")
+	think := flag.Duration("think", 100*time.Millisecond, "pause between a player's actions")
+	prefix := flag.String("prefix", "load", "player name prefix (players are <prefix>-0, <prefix>-1, ...)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed")
+	metrics := flag.String("metrics", "", "comma-separated /metrics base URLs, one per coordinator replica, to count region spawns from (default -addr, which only reaches one replica)")
+	local := flag.Bool("local", false, "start a coordinator here that runs regions as local processes, instead of using -addr")
+	coordinatorBin := flag.String("coordinator-bin", "coordinator", "coordinator binary for -local (path, or name on $PATH)")
+	regionBin := flag.String("region-bin", "region", "region binary for -local (path, or name on $PATH)")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address for -local")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), `Usage: loadtest [flags]
+
+Simulates players against a Coordinator: a running one at -addr, or with
+-local one started here with ORCHESTRATOR=local, which runs each region as
+a child process instead of a Kubernetes pod. -local needs only Redis and
+the binaries, e.g.:
+
+	go build -o bin/ ./cmd/coordinator ./cmd/region ./cmd/loadtest
+	bin/loadtest -local -coordinator-bin bin/coordinator -region-bin bin/region
+
+Region spawns are read from the Coordinator's /metrics. Behind a Service
+with several replicas that reaches only one of them, so pass every pod
+(e.g. port-forwarded) with -metrics for a full count.
+
+`)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *players < 1 || *lookRatio < 0 || *lookRatio > 1 {
+		fmt.Println("Need at least one player and a look ratio between 0 and 1")
+		os.Exit(2)
+	}
+	if *local {
+		url, stop, err := startLocal(*coordinatorBin, *regionBin, *redisAddr)
+		if err != nil {
+			fmt.Println("Can't start a local coordinator:", err)
+			os.Exit(1)
+		}
+		defer stop()
+		*addr = url
+	}
+	if *addr == "" {
+		*addr = "http://localhost:8080" // Default for local testing
+	}
+	metricsAddrs := []string{*addr}
+	if *metrics != "" {
+		metricsAddrs = strings.Split(*metrics, ",")
+	}
+
+	// Spawn counts come from the Coordinator's own metrics, summed over replicas
+	spawnsBefore, spawnsErr := readSpawns(metricsAddrs)
+
+	fmt.Printf("Running %d players against %s for %s (%.0f%% looks)\n", *players, *addr, *duration, *lookRatio*100)
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	rec := &recorder{}
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < *players; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("%s-%d", *prefix, i)
+			rng := rand.New(rand.NewSource(*seed + int64(i)))
+			simulatePlayer(ctx, *addr, name, rng, *lookRatio, *think, rec)
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report(rec.samples, elapsed)
+	spawnsAfter, err := readSpawns(metricsAddrs)
+	switch {
+	case spawnsErr != nil || err != nil:
+		fmt.Println("Region spawns: unknown (coordinator /metrics unavailable)")
+	case *local:
+		fmt.Printf("Region spawns: %.0f\n", spawnsAfter-spawnsBefore) // The only coordinator there is
+	case *metrics == "":
+		// Each scrape may land on a different replica, so this can be off either way
+		fmt.Printf("Region spawns: %.0f (one replica's count, unreliable with several; see -metrics)\n", spawnsAfter-spawnsBefore)
+	default:
+		fmt.Printf("Region spawns: %.0f across %d replicas\n", spawnsAfter-spawnsBefore, len(metricsAddrs))
+	}
+}
+
+const (
+	lowStamina    = 20 // Where a simulated player stops to rest
+	fleeHealth    = 10 // Where they stop attacking and flee
+	maxFightTurns = 30 // Fleeing can fail; give up on a fight after this many turns
+)
+
+// simulatePlayer moves one player around until ctx ends
+// Example: "load-3" starts at its saved position and random-walks, looking now and then
+func simulatePlayer(ctx context.Context, addr, name string, rng *rand.Rand, lookRatio float64, think time.Duration, rec *recorder) {
+	// No retries: every failure should show up in the error rate
+	c := coordclient.New(addr, name, coordclient.WithRetries(0), coordclient.WithTimeout(30*time.Second))
+	x, y := 0, 0
+	if pos, err := c.Position(ctx); err == nil {
+		x, y = pos.X, pos.Y
+	}
+
+	steps := [][2]int{{0, 1}, {1, 0}, {0, -1}, {-1, 0}}
+	for ctx.Err() == nil {
+		op, nx, ny := "look", x, y
+		if rng.Float64() >= lookRatio {
+			step := steps[rng.Intn(len(steps))]
+			op, nx, ny = "move", x+step[0], y+step[1]
+		}
+
+		var reply *coordclient.Reply
+		err := timed(ctx, rec, op, func() error {
+			var err error
+			if op == "move" {
+				reply, err = c.Move(ctx, nx, ny)
+			} else {
+				reply, err = c.Look(ctx, nx, ny)
+			}
+			return err
+		})
+		if ctx.Err() != nil {
+			return // Cut off by the deadline, not a real failure
+		}
+		if err == nil {
+			x, y = reply.X, reply.Y
+		}
+
+		// Something attacked on arrival, or the player was already fighting
+		// when the run began: every move is refused until the fight ends
+		// Example: "A grey wolf attacks!" -> attack until it's won or lost
+		var refusal *coordclient.StatusError
+		inFight := errors.As(err, &refusal) && strings.Contains(refusal.Message, "in a fight")
+		if err == nil && reply.Fight != nil && reply.Fight.Outcome == "" || inFight {
+			if fx, fy, ok := fightOut(ctx, c, rec); ok {
+				x, y = fx, fy
+			}
+			continue
+		}
+
+		// A worn-out player would walk into refusals forever; rest instead,
+		// like a real one would, when a move is refused or leaves them spent
+		// Example: "You're too exhausted to climb the hill" -> /rest
+		if op == "move" && (errors.As(err, &refusal) && refusal.Code < 500 ||
+			err == nil && reply.Vitals != nil && reply.Vitals.Stamina < lowStamina) {
+			timed(ctx, rec, "rest", func() error {
+				_, err := c.Rest(ctx)
+				return err
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(think):
+		}
+	}
+}
+
+// fightOut takes turns until the player's fight is over, attacking while
+// healthy and fleeing once hurt; returns where it left them, home after a defeat
+// Example: grey wolf 12/12 -> attack, attack, attack -> "won"
+func fightOut(ctx context.Context, c *coordclient.Client, rec *recorder) (x, y int, ok bool) {
+	action := "attack"
+	for turn := 0; turn < maxFightTurns && ctx.Err() == nil; turn++ {
+		var res *coordclient.FightReply
+		err := timed(ctx, rec, "fight", func() error {
+			var err error
+			res, err = c.Fight(ctx, action, "")
+			return err
+		})
+		var refusal *coordclient.StatusError
+		if errors.As(err, &refusal) && refusal.Code == http.StatusConflict {
+			continue // Another request played the turn; take the next one
+		}
+		if err != nil {
+			return 0, 0, false // Nothing to fight anymore, or the Coordinator is failing
+		}
+		if res.Fight == nil || res.Fight.Outcome != "" {
+			return res.X, res.Y, true
+		}
+		if res.Vitals != nil && res.Vitals.Health < fleeHealth {
+			action = "flee"
+		}
+	}
+	return 0, 0, false
+}
+
+// timed runs one request and records how it went, unless ctx ended it
+func timed(ctx context.Context, rec *recorder, op string, do func() error) error {
+	begin := time.Now()
+	err := do()
+	if ctx.Err() != nil {
+		return err
+	}
+	var refusal *coordclient.StatusError
+	refused := errors.As(err, &refusal) && refusal.Code >= 400 && refusal.Code < 500
+	rec.add(sample{op: op, latency: time.Since(begin), failed: err != nil && !refused, refused: refused})
+	return err
+}
+
+// report prints per-operation latency percentiles, refusals and error rates;
+// refusals are the game saying no, so they don't count as errors
+// Example:
+//
+//	op     count  refused  errors  err%   p50     p90     p99     max
+//	move   1200   40       3       0.25   45ms    180ms   2.1s    4.8s
+func report(samples []sample, elapsed time.Duration) {
+	byOp := map[string][]sample{}
+	for _, s := range samples {
+		byOp[s.op] = append(byOp[s.op], s)
+		byOp["all"] = append(byOp["all"], s)
+	}
+
+	fmt.Printf("\n%d requests in %s (%.1f req/s)\n", len(samples), elapsed.Round(time.Millisecond), float64(len(samples))/elapsed.Seconds())
+	fmt.Printf("%-6s %7s %8s %7s %7s %9s %9s %9s %9s\n", "op", "count", "refused", "errors", "err%", "p50", "p90", "p99", "max")
+	for _, op := range []string{"move", "look", "rest", "fight", "all"} {
+		list := byOp[op]
+		if len(list) == 0 {
+			continue
+		}
+		latencies := make([]time.Duration, len(list))
+		refused, failed := 0, 0
+		for i, s := range list {
+			latencies[i] = s.latency
+			if s.refused {
+				refused++
+			}
+			if s.failed {
+				failed++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		fmt.Printf("%-6s %7d %8d %7d %7.2f %9s %9s %9s %9s\n", op, len(list), refused, failed,
+			100*float64(failed)/float64(len(list)),
+			percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99),
+			latencies[len(latencies)-1].Round(time.Millisecond))
+	}
+}
+
+// percentile picks the p-th percentile from sorted latencies (nearest rank)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
+
+// readSpawns sums driftscape_coordinator_region_spawns_total over each
+// replica's /metrics; any one failing fails the count
+func readSpawns(addrs []string) (float64, error) {
+	total := 0.0
+	for _, addr := range addrs {
+		n, err := readReplicaSpawns(strings.TrimSpace(addr))
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// readReplicaSpawns sums driftscape_coordinator_region_spawns_total from one /metrics
+// Example: `..._spawns_total{result="ok"} 12` and `{result="error"} 1` -> 13
+func readReplicaSpawns(addr string) (float64, error) {
+	resp, err := httpClient.Get(addr + "/metrics")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("metrics: %s", resp.Status)
+	}
+
+	total := 0.0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "driftscape_coordinator_region_spawns_total") {
+			continue
+		}
+		fields := strings.Fields(line)
+		v, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if err == nil {
+			total += v
+		}
+	}
+	return total, scanner.Err()
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/items"
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+const (
+	encounterChance = 0.15 // Of being attacked on arrival, in daylight; doubled at night
+	fleeChance      = 0.6  // Of getting away when fleeing
+)
+
+// foe is a creature that attacks players
+type foe struct {
+	Name   string `json:"name"`
+	Health int    `json:"max_health"`
+	MinHit int    `json:"min_hit"`
+	MaxHit int    `json:"max_hit"`
+	Loot   string `json:"loot"` // Left on the ground when it's beaten
+}
+
+// foes are who attacks where, by cave or ruins first, then base terrain
+// Example: "forest with ancient ruins" -> a restless skeleton
+var foes = []struct {
+	word string
+	foes []foe
+}{
+	{"cave", []foe{{"a cave bear", 18, 3, 7, "bear claw"}}},
+	{"ruins", []foe{{"a restless skeleton", 10, 2, 5, "old coin"}}},
+	{"forest", []foe{{"a grey wolf", 12, 2, 5, "wolf pelt"}, {"an angry boar", 14, 2, 4, "tusk"}}},
+	{"plains", []foe{{"a wild dog", 8, 1, 4, "bone"}}},
+	{"hill", []foe{{"a mountain lion", 14, 3, 6, "claw"}}},
+	{"swamp", []foe{{"a bog lurker", 10, 2, 5, "slime"}}},
+}
+
+// fightItems are what helps in a fight: healing, or scaring the foe off
+var fightItems = map[string]struct {
+	heal   int
+	scares bool
+}{
+	"herb":     {heal: 8},
+	"fish":     {heal: 6},
+	"mushroom": {heal: 4},
+	"torch":    {scares: true},
+}
+
+// fight is a player's fight in progress, stored in the region's Redis hash
+type fight struct {
+	Foe    foe   `json:"foe"`
+	Health int   `json:"health"` // The foe's
+	Seed   int64 `json:"seed"`   // With Turn, seeds each turn's rolls
+	Turn   int   `json:"turn"`
+}
+
+func (f fight) proto() *pb.Fight {
+	return &pb.Fight{Foe: f.Foe.Name, FoeHealth: int32(max(0, f.Health)), FoeMaxHealth: int32(f.Foe.Health), Turn: int32(f.Turn)}
+}
+
+// errStaleTurn is a turn asked for after another was played in its place
+var errStaleTurn = errors.New("stale turn")
+
+func fightsKey(x, y int) string {
+	// Redis hash of fights going on in a region, by player
+	// Example: "region:2,4:fights" -> {"alice": {"foe": {"name": "a grey wolf", ...}, "health": 7, ...}}
+	return fmt.Sprintf("region:%d,%d:fights", x, y)
+}
+
+func visitsKey(x, y int) string {
+	// Counts arrivals, so each visit rolls its own encounter
+	return fmt.Sprintf("region:%d,%d:visits", x, y)
+}
+
+func the(name string) string {
+	// Example: "a grey wolf" -> "the grey wolf"
+	_, rest, ok := strings.Cut(name, " ")
+	if !ok {
+		return name
+	}
+	return "the " + rest
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func loadFight(ctx context.Context, c redis.Cmdable, x, y int, player string) (fight, error) {
+	var f fight
+	data, err := c.HGet(ctx, fightsKey(x, y), player).Result()
+	if err != nil {
+		return f, err
+	}
+	err = json.Unmarshal([]byte(data), &f)
+	return f, err
+}
+
+func (s *regionServer) Encounter(ctx context.Context, req *pb.EncounterRequest) (*pb.Fight, error) {
+	// Roll whether something attacks a player arriving here
+	// Example: (2,4) "forest", 7th visit, at night -> "A grey wolf attacks!"
+	if req.Position == nil || req.Player == "" {
+		return nil, status.Error(codes.InvalidArgument, "position and player are required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	if f, err := loadFight(ctx, rdb, x, y, req.Player); err == nil {
+		return f.proto(), nil // Still fighting from before
+	} else if err != redis.Nil {
+		slog.ErrorContext(ctx, "Reading fight failed", "player", req.Player, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+
+	// The same visit to the same region always rolls the same way
+	visit, err := rdb.Incr(ctx, visitsKey(x, y)).Result()
+	if err != nil {
+		slog.ErrorContext(ctx, "Counting visits failed", "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("encounter:%d,%d:%d", x, y, visit)))
+	r := newRand(int64(h.Sum64()) ^ worldSeed)
+
+	chance := encounterChance
+	if now, err := world.ReadClock(ctx, rdb); err == nil && now.Phase() == "night" {
+		chance *= 2
+	}
+	if built, err := listStructures(ctx, x, y); err == nil {
+		for _, st := range built {
+			if st.Kind == "camp" {
+				chance /= 2 // Creatures keep away from a camp
+				break
+			}
+		}
+	}
+	if r.Float64() >= chance {
+		return &pb.Fight{}, nil
+	}
+
+	terrain := world.Terrain(x, y)
+	var table []foe
+	for _, t := range foes {
+		if strings.Contains(terrain, t.word) {
+			table = t.foes
+			break
+		}
+	}
+	if len(table) == 0 {
+		return &pb.Fight{}, nil
+	}
+	foe := table[r.Intn(len(table))]
+	f := fight{Foe: foe, Health: foe.Health, Seed: r.Int63()}
+	data, _ := json.Marshal(f)
+	if err := rdb.HSet(ctx, fightsKey(x, y), req.Player, data).Err(); err != nil {
+		slog.ErrorContext(ctx, "Saving fight failed", "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	slog.InfoContext(ctx, "Encounter", "player", req.Player, "foe", foe.Name, "visit", visit)
+	fightsStarted.Inc()
+	res := f.proto()
+	res.Log = capitalize(foe.Name) + " attacks!"
+	return res, nil
+}
+
+func (s *regionServer) FightTurn(ctx context.Context, req *pb.FightRequest) (*pb.Fight, error) {
+	// Play the player's action, then the foe's if the fight goes on
+	// Example: attack with a rusty sword -> "You hit the grey wolf for 8. The grey wolf strikes you for 3."
+	if req.Position == nil || req.Player == "" {
+		return nil, status.Error(codes.InvalidArgument, "position and player are required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+
+	// Each turn is played once: it only goes ahead if the fight is still at
+	// the turn the caller saw, and nobody else played it meanwhile
+	var f fight
+	var res *pb.Fight
+	err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+		var err error
+		if f, err = loadFight(ctx, tx, x, y, req.Player); err != nil {
+			return err
+		}
+		if f.Turn != int(req.Turn) {
+			return errStaleTurn
+		}
+		if res, err = playTurn(&f, req); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if res.Outcome == "won" {
+				pipe.HIncrBy(ctx, itemsKey(x, y), f.Foe.Loot, 1)
+			}
+			if res.Outcome != "" {
+				pipe.HDel(ctx, fightsKey(x, y), req.Player)
+				return nil
+			}
+			data, _ := json.Marshal(f)
+			pipe.HSet(ctx, fightsKey(x, y), req.Player, data)
+			return nil
+		})
+		return err
+	}, fightsKey(x, y))
+	switch {
+	case err == redis.Nil:
+		return nil, status.Error(codes.NotFound, "there's nothing to fight here")
+	case errors.Is(err, errStaleTurn) || errors.Is(err, redis.TxFailedErr):
+		return nil, status.Error(codes.FailedPrecondition, "that turn was already played")
+	case status.Code(err) == codes.InvalidArgument:
+		return nil, err
+	case err != nil:
+		slog.ErrorContext(ctx, "Playing fight turn failed", "player", req.Player, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+
+	if res.Outcome != "" {
+		fightsEnded.WithLabelValues(res.Outcome).Inc()
+		slog.InfoContext(ctx, "Fight over", "player", req.Player, "foe", f.Foe.Name, "outcome", res.Outcome, "turns", f.Turn)
+	}
+	return res, nil
+}
+
+// playTurn rolls one turn of f, moving it on; f.Seed and f.Turn make the rolls
+func playTurn(f *fight, req *pb.FightRequest) (*pb.Fight, error) {
+	r := newRand(f.Seed + int64(f.Turn))
+	f.Turn++
+	res := &pb.Fight{}
+	var log []string
+	switch req.Action {
+	case "attack":
+		hit := 3 + r.Intn(4) + items.Weapons[req.Weapon]
+		f.Health -= hit
+		if req.Weapon != "" {
+			log = append(log, fmt.Sprintf("You hit %s with your %s for %d", the(f.Foe.Name), req.Weapon, hit))
+		} else {
+			log = append(log, fmt.Sprintf("You hit %s for %d", the(f.Foe.Name), hit))
+		}
+		if f.Health <= 0 {
+			res.Outcome = "won"
+			log = append(log, fmt.Sprintf("%s falls, leaving a %s behind", capitalize(the(f.Foe.Name)), f.Foe.Loot))
+		}
+	case "flee":
+		if r.Float64() < fleeChance {
+			res.Outcome = "fled"
+			log = append(log, fmt.Sprintf("You get away from %s", the(f.Foe.Name)))
+		} else {
+			log = append(log, fmt.Sprintf("You try to run, but %s cuts you off", the(f.Foe.Name)))
+		}
+	case "use":
+		effect, ok := fightItems[req.Item]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "a %s is no use in a fight", req.Item)
+		}
+		res.ItemUsed = true
+		if effect.scares {
+			res.Outcome = "scared off"
+			log = append(log, fmt.Sprintf("You wave the %s and %s runs off", req.Item, the(f.Foe.Name)))
+		} else {
+			res.Healed = int32(effect.heal)
+			log = append(log, fmt.Sprintf("You use the %s and feel better (+%d health)", req.Item, effect.heal))
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "you can attack, flee or use an item, not %q", req.Action)
+	}
+
+	if res.Outcome == "" {
+		// The foe strikes back
+		hit := f.Foe.MinHit + r.Intn(f.Foe.MaxHit-f.Foe.MinHit+1)
+		res.Damage = int32(hit)
+		log = append(log, fmt.Sprintf("%s strikes you for %d", capitalize(the(f.Foe.Name)), hit))
+		if req.Health+res.Healed-res.Damage <= 0 {
+			res.Outcome = "defeated"
+			log = append(log, "You fall")
+		}
+	}
+	res.Foe, res.FoeHealth, res.FoeMaxHealth, res.Turn = f.Foe.Name, int32(max(0, f.Health)), int32(f.Foe.Health), int32(f.Turn)
+	res.Log = strings.Join(log, ". ") + "."
+	return res, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// resourceRegrowChance is per npcTick and scaled, like the other simulation chances
+const resourceRegrowChance = 0.05
+
+// resources are what can be gathered where a terrain word appears, and
+// how much of each a region holds when it's grown back fully
+// Example: "hill with a river" -> stone, iron ore and clay
+var resources = []struct {
+	word string
+	name string
+	max  int
+}{
+	{"forest", "wood", 6},
+	{"forest", "resin", 3},
+	{"hill", "stone", 6},
+	{"hill", "iron ore", 2},
+	{"swamp", "reed", 8},
+	{"swamp", "peat", 4},
+	{"river", "clay", 4},
+}
+
+func resourcesKey(x, y int) string {
+	// Redis hash of what's left of each resource; a missing field is fully grown
+	// Example: "region:2,4:resources" -> {"wood": "2"}
+	return fmt.Sprintf("region:%d,%d:resources", x, y)
+}
+
+// gatherScript takes one of resource ARGV[1] from KEYS[1], treating a missing
+// field as ARGV[2] (fully grown); returns what's left, or -1 if there was none
+var gatherScript = redis.NewScript(`
+local have = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or ARGV[2])
+if have < 1 then
+	return -1
+end
+redis.call("HSET", KEYS[1], ARGV[1], have - 1)
+return have - 1
+`)
+
+// regrowScript grows back one of resource ARGV[1] in KEYS[1], up to ARGV[2];
+// reaching it drops the field, since missing means fully grown; returns the new amount
+var regrowScript = redis.NewScript(`
+local full = tonumber(ARGV[2])
+local have = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or ARGV[2])
+if have >= full - 1 then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+else
+	redis.call("HSET", KEYS[1], ARGV[1], have + 1)
+end
+return math.min(have + 1, full)
+`)
+
+// regionResources lists what grows in a region and how much is left
+// Example: (2,4) "forest" -> [wood 2/6] [resin 3/3]
+func regionResources(ctx context.Context, x, y int, terrain string) ([]*pb.Resource, error) {
+	hash, err := rdb.HGetAll(ctx, resourcesKey(x, y)).Result()
+	if err != nil {
+		return nil, err
+	}
+	var list []*pb.Resource
+	for _, res := range resources {
+		if !strings.Contains(terrain, res.word) {
+			continue
+		}
+		left := res.max
+		if v, ok := hash[res.name]; ok {
+			left, _ = strconv.Atoi(v)
+		}
+		list = append(list, &pb.Resource{Name: res.name, Amount: int32(left), Max: int32(res.max)})
+	}
+	return list, nil
+}
+
+func (s *regionServer) Gather(ctx context.Context, req *pb.GatherRequest) (*pb.Gathered, error) {
+	// Take one of a resource growing here
+	// Example: "reed" at (3,1) "swamp" with 5 left -> 4 left
+	if req.Position == nil {
+		return nil, status.Error(codes.InvalidArgument, "position is required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	terrain := world.Terrain(x, y)
+	list, err := regionResources(ctx, x, y, terrain)
+	if err != nil {
+		slog.ErrorContext(ctx, "Reading resources failed", "x", x, "y", y, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	if len(list) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "there's nothing to gather in the %s", strings.Fields(terrain)[0])
+	}
+	if state, err := readState(ctx, x, y); err == nil && state.fire > 0 {
+		return nil, status.Error(codes.FailedPrecondition, "it's too hot to gather anything while it burns")
+	}
+
+	// Nothing asked for: the most plentiful; else it has to grow here
+	// Example: [wood 2/6] [resin 3/3] -> resin
+	var pick *pb.Resource
+	for _, res := range list {
+		if req.Resource == "" && (pick == nil || res.Amount > pick.Amount) || res.Name == req.Resource {
+			pick = res
+		}
+	}
+	if pick == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no %s grows here", req.Resource)
+	}
+	left, err := gatherScript.Run(ctx, rdb, []string{resourcesKey(x, y)}, pick.Name, pick.Max).Int()
+	if err != nil {
+		slog.ErrorContext(ctx, "Gathering failed", "x", x, "y", y, "resource", pick.Name, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	if left < 0 {
+		return nil, status.Errorf(codes.NotFound, "there's no %s left here; give it time to grow back", pick.Name)
+	}
+	pick.Amount = int32(left)
+	resourcesGathered.WithLabelValues(pick.Name).Inc()
+	slog.DebugContext(ctx, "Gathered", "x", x, "y", y, "resource", pick.Name, "left", left)
+	return &pb.Gathered{Resource: pick.Name, Resources: list}, nil
+}
+
+// regrowResource grows back one of a resource the region has lost
+// Example: wood 2/6 -> 3/6; once full the field is dropped
+func regrowResource(ctx context.Context, x, y int, terrain string, r *rand.Rand) {
+	list, err := regionResources(ctx, x, y, terrain)
+	if err != nil {
+		slog.WarnContext(ctx, "Reading resources failed", "err", err)
+		return
+	}
+	var short []*pb.Resource
+	for _, res := range list {
+		if res.Amount < res.Max {
+			short = append(short, res)
+		}
+	}
+	if len(short) == 0 {
+		return
+	}
+	// Checked again in the script, as a gather may have come in since
+	res := short[r.Intn(len(short))]
+	if err := regrowScript.Run(ctx, rdb, []string{resourcesKey(x, y)}, res.Name, res.Max).Err(); err != nil {
+		slog.WarnContext(ctx, "Regrowing resource failed", "resource", res.Name, "err", err)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// interiorDepth is how many rooms deep an interior goes below its entrance
+const interiorDepth = 3
+
+// interiors lists what enterable features are made of
+// The feature word in the terrain picks the kind, e.g. "forest with a cave" -> cave
+var interiors = []struct {
+	kind     string // Also the first element of every room path
+	entrance string
+	rooms    []string
+	details  []string
+}{
+	{
+		kind:     "cave",
+		entrance: "cave mouth",
+		rooms:    []string{"narrow tunnel", "dripping chamber", "crystal grotto", "underground pool", "bat roost", "collapsed passage"},
+		details: []string{"Water drips somewhere in the dark.", "The air is cold and still.",
+			"Your footsteps echo back at you.", "Something skitters away from the light."},
+	},
+	{
+		kind:     "ruins",
+		entrance: "ruined gateway",
+		rooms:    []string{"crumbling hall", "collapsed tower", "overgrown courtyard", "sunken crypt", "broken library", "forgotten shrine"},
+		details: []string{"Moss covers the carved stones.", "A cold draft moves through the cracks.",
+			"Faded murals line the walls.", "Rubble shifts under your feet."},
+	},
+}
+
+func interiorKind(terrain string) string {
+	// Which interior a region has, if any
+	// Example: "hill with ancient ruins" -> "ruins", "plains" -> ""
+	for _, in := range interiors {
+		if strings.Contains(terrain, in.kind) {
+			return in.kind
+		}
+	}
+	return ""
+}
+
+// roomLayout is what's generated for one room path
+type roomLayout struct {
+	name     string
+	detail   string
+	children int // Exits deeper in, numbered from 1
+}
+
+// layoutRoom generates a room from the world seed, its region and its path
+// Example: (2,4) "cave/2" -> {"crystal grotto", "The air is cold and still.", 1}, the same every time
+func layoutRoom(x, y int, path string) roomLayout {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("room:%d,%d:%s", x, y, path)))
+	r := newRand(int64(h.Sum64()) ^ worldSeed)
+
+	steps := strings.Split(path, "/")
+	kind, depth := steps[0], len(steps)-1
+	for _, in := range interiors {
+		if in.kind != kind {
+			continue
+		}
+		l := roomLayout{name: in.entrance, detail: in.details[r.Intn(len(in.details))]}
+		if depth > 0 {
+			l.name = in.rooms[r.Intn(len(in.rooms))]
+		}
+		switch {
+		case depth == 0:
+			l.children = 1 + r.Intn(3) // The entrance always leads somewhere
+		case depth < interiorDepth:
+			l.children = r.Intn(3) // Dead ends happen
+		}
+		return l
+	}
+	return roomLayout{}
+}
+
+// validRoomPath checks each step of path exists in the region's interior
+// Example: "cave/2/1" is valid if the entrance has 2+ exits and room 2 has 1+
+func validRoomPath(x, y int, kind, path string) bool {
+	steps := strings.Split(path, "/")
+	if steps[0] != kind {
+		return false
+	}
+	at := kind
+	for _, step := range steps[1:] {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 || n > layoutRoom(x, y, at).children || step != strconv.Itoa(n) {
+			return false // "02" would be a second name for room 2
+		}
+		at += "/" + step
+	}
+	return true
+}
+
+func (s *regionServer) GetRoom(ctx context.Context, req *pb.RoomRequest) (*pb.Room, error) {
+	// Describe a room inside the region's cave or ruins
+	// Example: (2,4) "" -> the cave mouth, with exits "cave/1" and "cave/2"
+	if req.Position == nil {
+		return nil, status.Error(codes.InvalidArgument, "position is required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	kind := interiorKind(world.Terrain(x, y))
+	if kind == "" {
+		return nil, status.Error(codes.NotFound, "there's nothing to enter here")
+	}
+	path := req.Path
+	if path == "" {
+		path = kind
+	}
+	if !validRoomPath(x, y, kind, path) {
+		return nil, status.Errorf(codes.NotFound, "no room %q here", path)
+	}
+
+	l := layoutRoom(x, y, path)
+	room := &pb.Room{
+		Path:        path,
+		Name:        l.name,
+		Description: fmt.Sprintf("You're in a %s. %s", l.name, l.detail),
+	}
+	for i := 1; i <= l.children; i++ {
+		child := fmt.Sprintf("%s/%d", path, i)
+		room.Exits = append(room.Exits, &pb.Exit{Name: layoutRoom(x, y, child).name, Path: child})
+	}
+	return room, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/items"
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+func itemsKey(x, y int) string {
+	// Redis hash of items on the ground
+	// Example: "region:2,4:items" -> {"stick": 2}
+	return fmt.Sprintf("region:%d,%d:items", x, y)
+}
+
+func itemsSeededKey(x, y int) string {
+	// Set once a region's starting items were placed, so taking
+	// everything doesn't make them grow back on the next visit
+	return fmt.Sprintf("region:%d,%d:items-seeded", x, y)
+}
+
+// generateItems picks a region's starting items from the world seed
+// Example: (2,4) "forest with a cave" -> {"stick": 2, "torch": 1}, the same every time
+func generateItems(x, y int, terrain string) map[string]int {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("items:%d,%d", x, y)))
+	r := newRand(int64(h.Sum64()) ^ worldSeed)
+
+	found := map[string]int{}
+	for _, table := range items.LootTables {
+		if !strings.Contains(terrain, table.Word) {
+			continue
+		}
+		for _, name := range table.Items {
+			if r.Float32() < 0.4 { // Not everything is lying around
+				found[name] += 1 + r.Intn(3)
+			}
+		}
+	}
+	return found
+}
+
+// ensureItems places a region's starting items the first time it's visited
+func ensureItems(ctx context.Context, x, y int, terrain string) error {
+	first, err := rdb.SetNX(ctx, itemsSeededKey(x, y), 1, 0).Result()
+	if err != nil || !first {
+		return err
+	}
+	found := generateItems(x, y, terrain)
+	if len(found) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(found))
+	for name, n := range found {
+		fields[name] = n
+	}
+	slog.DebugContext(ctx, "Items placed", "x", x, "y", y, "items", found)
+	return rdb.HSet(ctx, itemsKey(x, y), fields).Err()
+}
+
+// groundItems lists what's on the ground, for replies
+func groundItems(ctx context.Context, x, y int) ([]*pb.Item, error) {
+	hash, err := rdb.HGetAll(ctx, itemsKey(x, y)).Result()
+	if err != nil {
+		return nil, err
+	}
+	var list []*pb.Item
+	for _, s := range items.Stacks(hash) {
+		list = append(list, &pb.Item{Name: s.Name, Count: int32(s.Count)})
+	}
+	return list, nil
+}
+
+// checkItemRequest validates a take or drop
+func checkItemRequest(req *pb.ItemRequest) error {
+	if req.Position == nil {
+		return status.Error(codes.InvalidArgument, "position is required")
+	}
+	if !items.ValidName(req.Name) {
+		return status.Errorf(codes.InvalidArgument, "bad item name %q", req.Name)
+	}
+	if req.Count < 1 || req.Count > items.MaxCount {
+		return status.Errorf(codes.InvalidArgument, "count must be 1 to %d", items.MaxCount)
+	}
+	return nil
+}
+
+func (s *regionServer) TakeItem(ctx context.Context, req *pb.ItemRequest) (*pb.Items, error) {
+	// Pick items up off the ground
+	// Example: Take 1 "stick" at (2,4) -> remaining [stick 1]
+	if err := checkItemRequest(req); err != nil {
+		return nil, err
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	// Items must be placed before the first take, even if nobody looked yet
+	if err := ensureItems(ctx, x, y, world.Terrain(x, y)); err != nil {
+		slog.ErrorContext(ctx, "Placing items failed", "x", x, "y", y, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+
+	ok, err := items.Take(ctx, rdb, itemsKey(x, y), req.Name, int(req.Count))
+	if err != nil {
+		slog.ErrorContext(ctx, "Taking item failed", "x", x, "y", y, "item", req.Name, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "not enough %s here", req.Name)
+	}
+	list, err := groundItems(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing items failed", "x", x, "y", y, "err", err)
+	}
+	return &pb.Items{Items: list}, nil
+}
+
+func (s *regionServer) DropItem(ctx context.Context, req *pb.ItemRequest) (*pb.Items, error) {
+	// Put items down on the ground
+	// Example: Drop 2 "flint" at (2,4) -> remaining [flint 2, stick 1]
+	if err := checkItemRequest(req); err != nil {
+		return nil, err
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	// Place starting items first so they don't overwrite what's dropped
+	if err := ensureItems(ctx, x, y, world.Terrain(x, y)); err != nil {
+		slog.ErrorContext(ctx, "Placing items failed", "x", x, "y", y, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	if err := rdb.HIncrBy(ctx, itemsKey(x, y), req.Name, int64(req.Count)).Err(); err != nil {
+		slog.ErrorContext(ctx, "Dropping item failed", "x", x, "y", y, "item", req.Name, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	list, err := groundItems(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing items failed", "x", x, "y", y, "err", err)
+	}
+	return &pb.Items{Items: list}, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/telemetry"
+)
+
+// requestIDInterceptor picks up the Coordinator's request ID and logs each call
+// Example: metadata "x-request-id: 9f2c..." -> every log line carries request_id
+func requestIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	id := telemetry.NewRequestID()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(telemetry.RequestIDHeader); len(ids) > 0 && telemetry.ValidRequestID(ids[0]) {
+			id = ids[0]
+		}
+	}
+	ctx = telemetry.WithRequestID(ctx, id)
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, "rpc handled",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+	return resp, err
+}
@@ -3,93 +3,218 @@ package main
 import (
 	"context"
 	"fmt"
-	"hash/fnv"
+	"log/slog"
 	"math/rand"
 	"net"
-	"strings"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 
+	"github.com/akos011221/driftscape/internal/cluster"
+	"github.com/akos011221/driftscape/internal/telemetry"
+	"github.com/akos011221/driftscape/internal/world"
 	pb "github.com/akos011221/driftscape/proto"
 )
 
 var (
-	rdb    *redis.Client
-	domain = "default.svc.cluster.local"
+	rdb *redis.Client
+
+	// shutdownTimeout bounds GracefulStop before in-flight RPCs are cut
+	shutdownTimeout = 20 * time.Second
+
+	// worldSeed varies generated content between worlds (WORLD_SEED)
+	// Example: Same seed -> same items at (2,4) after a pod restart
+	worldSeed int64
 )
 
 func main() {
-	// Connect to Redis for terrain storage
+	// Log JSON to stdout, tagged with this region's name
+	slog.SetDefault(telemetry.SetupLogging("region").With("region", regionLabels["region"]))
+
+	// Export traces (OTLP collector or stdout, see telemetry.SetupTracing)
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), "driftscape-region")
+	if err != nil {
+		slog.Warn("Tracing setup failed", "err", err)
+	} else {
+		defer shutdownTracing(context.Background())
+	}
+
+	if v := os.Getenv("SIM_TICK"); v != "" {
+		simTick, err = time.ParseDuration(v)
+		if err != nil || simTick <= 0 {
+			slog.Error("Bad SIM_TICK", "value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+	if v := os.Getenv("WORLD_SEED"); v != "" {
+		worldSeed, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			slog.Error("Bad WORLD_SEED", "value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	// Connect to Redis for terrain storage (REDIS_ADDR, or the in-cluster Service)
 	// Example: "region:2,4" -> "plains with a hill"
 	rdb = redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("redis.%s:6379", domain),
+		Addr: cluster.RedisAddr(),
 	})
-	_, err := rdb.Ping(context.Background()).Result()
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		slog.Warn("Redis tracing failed", "err", err)
+	}
+	_, err = rdb.Ping(context.Background()).Result()
 	if err != nil {
-		fmt.Println("Redis connection failed:", err)
+		slog.Error("Redis connection failed", "err", err)
 	}
 
-	// Start gRPC server on :8081
+	// Start gRPC server on :8081, or LISTEN_ADDR when run as a local process
 	// Listens for Coordinator calls to region services
-	lis, err := net.Listen("tcp", ":8081")
+	addr := envOr("LISTEN_ADDR", ":8081")
+	lis, err := net.Listen("tcp", addr)
 	if err != nil {
-		fmt.Println("Failed to listen:", err)
-		return
+		slog.Error("Failed to listen", "err", err)
+		os.Exit(1)
+	}
+	metricsServer := serveMetrics(envOr("METRICS_ADDR", ":9091"))
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metricsInterceptor, requestIDInterceptor),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()), // Joins the Coordinator's trace
+	)
+	// This pod's own cell, named by the Coordinator at spawn time
+	x, errX := strconv.Atoi(os.Getenv("REGION_X"))
+	y, errY := strconv.Atoi(os.Getenv("REGION_Y"))
+	placed := errX == nil && errY == nil
+	pb.RegisterRegionServiceServer(s, &regionServer{x: x, y: y, placed: placed})
+	// Stop on SIGTERM (Kubernetes) or SIGINT (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	go func() {
+		slog.Info("Region running", "addr", addr)
+		if err := s.Serve(lis); err != nil {
+			slog.Error("Failed to serve", "err", err)
+			stop()
+		}
+	}()
+
+	// Simulate this pod's own cell
+	if placed {
+		markVisit() // Spawned for a player who's on their way
+		go runSimulation(ctx, x, y)
+	} else {
+		slog.Warn("REGION_X/REGION_Y not set, simulation disabled")
 	}
-	s := grpc.NewServer()
-	pb.RegisterRegionServiceServer(s, &regionServer{})
-	fmt.Println("Region running on :8081")
-	if err := s.Serve(lis); err != nil {
-		fmt.Println("Failed to serve:", err)
+	<-ctx.Done()
+
+	// Finish in-flight GetDescription calls, then force-stop if they hang
+	// Example: Coordinator's call started before SIGTERM still gets its terrain
+	slog.Info("Shutting down, draining in-flight RPCs")
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		slog.Warn("Drain timed out, stopping")
+		s.Stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	metricsServer.Shutdown(shutdownCtx)
+	if err := rdb.Close(); err != nil {
+		slog.Error("Closing Redis failed", "err", err)
+	}
+	slog.Info("Region stopped")
+}
+
+func envOr(name, fallback string) string {
+	// Example: LISTEN_ADDR unset -> ":8081"
+	if v := os.Getenv(name); v != "" {
+		return v
 	}
+	return fallback
 }
 
 type regionServer struct {
 	pb.UnimplementedRegionServiceServer
+	x, y   int  // This pod's own cell (REGION_X, REGION_Y)
+	placed bool // Whether it has one; if not, nothing here is simulated
 }
 
 func (s *regionServer) GetDescription(ctx context.Context, pos *pb.Position) (*pb.Description, error) {
 	// Generate terrain based on x,y
 	// Example: (2,4) -> "plains with a hill"
 	x, y := int(pos.X), int(pos.Y)
-	terrain := generateTerrain(x, y)
+	start := time.Now()
+	terrain := world.Terrain(x, y)
+	generationDuration.Observe(time.Since(start).Seconds())
 
 	// Save to Redis
 	key := fmt.Sprintf("region:%d,%d", x, y)
-	rdb.Set(context.Background(), key, terrain, 0)
+	if err := rdb.Set(ctx, key, terrain, 0).Err(); err != nil {
+		slog.ErrorContext(ctx, "Saving terrain failed", "key", key, "err", err)
+	}
+	slog.DebugContext(ctx, "Terrain generated", "x", x, "y", y, "terrain", terrain)
 
-	return &pb.Description{Terrain: terrain}, nil
-}
+	// Items and NPCs are optional; a Redis hiccup still returns the terrain
+	if err := ensureItems(ctx, x, y, terrain); err != nil {
+		slog.ErrorContext(ctx, "Placing items failed", "x", x, "y", y, "err", err)
+	}
+	list, err := groundItems(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing items failed", "x", x, "y", y, "err", err)
+	}
 
-func generateTerrain(x, y int) string {
-	// Seed randomness with x,y for consistency
-	// Example: (2,4) always gets same base terrain
-	h := fnv.New32a()
-	h.Write([]byte(fmt.Sprintf("%d,%d", x, y)))
-	seed := h.Sum32()
-	r := newRand(int64(seed))
-
-	// Base terrain types
-	bases := []string{"forest", "plains", "hill", "swamp"}
-	base := bases[r.Intn(len(bases))]
-
-	// Add features with border sync
-	// Example: If (2,3) has a river south, (2,4) reflects it
-	feature := ""
-	if r.Float32() < 0.3 { // 30% chance of a feature
-		features := []string{"with a river", "with a cave", "with ancient ruins", "with a hill"}
-		feature = " " + features[r.Intn(len(features))]
-		// Check the south neighbor for river
-		southKey := fmt.Sprintf("region:%d,%d", x, y-1)
-		southTerrain, _ := rdb.Get(context.Background(), southKey).Result()
-		if strings.Contains(feature, "river") && strings.Contains(southTerrain, "river") {
-			feature = " with a river flowing south"
-		}
+	var npcs []*pb.NPC
+	if err := ensureNPCs(ctx, x, y, terrain); err != nil {
+		slog.ErrorContext(ctx, "Placing NPCs failed", "x", x, "y", y, "err", err)
+	}
+	present, err := listNPCs(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing NPCs failed", "x", x, "y", y, "err", err)
 	}
-	// Combine for richer description
-	// Example: "plains with a river flowing south"
-	return base + feature
+	for _, n := range present {
+		npcs = append(npcs, n.proto())
+	}
+
+	state, err := readState(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Reading region state failed", "x", x, "y", y, "err", err)
+	}
+
+	built, err := listStructures(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing structures failed", "x", x, "y", y, "err", err)
+	}
+	var structures []*pb.Structure
+	for _, st := range built {
+		structures = append(structures, st.proto())
+	}
+
+	growing, err := regionResources(ctx, x, y, terrain)
+	if err != nil {
+		slog.WarnContext(ctx, "Reading resources failed", "x", x, "y", y, "err", err)
+	}
+
+	return &pb.Description{
+		Terrain:    terrain,
+		Items:      list,
+		Npcs:       npcs,
+		Conditions: state.conditions(terrain),
+		Weather:    describeWeather(ctx, x, y, terrain, hasBridge(built)),
+		Structures: structures,
+		Resources:  growing,
+	}, nil
 }
 
 // newRand creates a seeded random generator
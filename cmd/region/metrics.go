@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// regionLabels tie every series to the Deployment/HPA that owns this pod
+// Example: REGION_X=2, REGION_Y=-4 -> region="region-2--4"
+var regionLabels = prometheus.Labels{
+	"region": fmt.Sprintf("region-%s-%s", os.Getenv("REGION_X"), os.Getenv("REGION_Y")),
+}
+
+// Metrics exposed on the Region's :9091/metrics endpoint
+var (
+	descriptionCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "driftscape_region_get_description_total",
+		Help:        "GetDescription calls served by this region, by status code.",
+		ConstLabels: regionLabels,
+	}, []string{"code"})
+
+	generationDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:        "driftscape_region_generation_duration_seconds",
+		Help:        "Time spent generating terrain for a description.",
+		ConstLabels: regionLabels,
+		Buckets:     []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+	})
+
+	npcHandoffs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "driftscape_region_npc_handoffs_total",
+		Help:        "NPCs this region tried to hand to a neighbour, by result (ok or error).",
+		ConstLabels: regionLabels,
+	}, []string{"result"})
+
+	simTicks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "driftscape_region_sim_ticks_total",
+		Help:        "Simulation ticks, by state (active; idle when nobody is around; standby when another replica simulates).",
+		ConstLabels: regionLabels,
+	}, []string{"state"})
+
+	simTickDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:        "driftscape_region_sim_tick_duration_seconds",
+		Help:        "Time spent advancing the region by one active tick.",
+		ConstLabels: regionLabels,
+		Buckets:     []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	})
+
+	fightsStarted = promauto.NewCounter(prometheus.CounterOpts{
+		Name:        "driftscape_region_fights_started_total",
+		Help:        "Encounters where a creature attacked an arriving player.",
+		ConstLabels: regionLabels,
+	})
+
+	fightsEnded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "driftscape_region_fights_ended_total",
+		Help:        "Fights that ended, by outcome (won, fled, scared off or defeated).",
+		ConstLabels: regionLabels,
+	}, []string{"outcome"})
+
+	resourcesGathered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "driftscape_region_resources_gathered_total",
+		Help:        "Resources gathered by players, by resource.",
+		ConstLabels: regionLabels,
+	}, []string{"resource"})
+)
+
+// serveMetrics exposes /metrics over plain HTTP next to the gRPC port
+// Example: Prometheus scrapes region-2-4 pod on :9091/metrics
+func serveMetrics(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Metrics server failed", "err", err)
+		}
+	}()
+	return server
+}
+
+// metricsInterceptor counts GetDescription calls by result, and notes
+// player visits (anything but a neighbour's NPC handoff) for the simulation
+func metricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if info.FullMethod != pb.RegionService_HandoffNPC_FullMethodName {
+		markVisit()
+	}
+	resp, err := handler(ctx, req)
+	if info.FullMethod == pb.RegionService_GetDescription_FullMethodName {
+		descriptionCalls.WithLabelValues(status.Code(err).String()).Inc()
+	}
+	return resp, err
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/cluster"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// npcTick is the period the NPC chances below are tuned for
+const npcTick = 10 * time.Second
+
+// npcKinds lists who can live in a region and how restless they are
+// Creatures are picked by base terrain, e.g. "forest" -> "a red fox"
+var npcKinds = []struct {
+	kind   string
+	chance float32 // Of being placed in a new region
+	wander float32 // Of leaving for a neighbouring region each tick
+	names  []string
+}{
+	{"wanderer", 0.2, 0.3, []string{"a weary wanderer", "a hooded pilgrim", "a lost hunter"}},
+	{"trader", 0.1, 0.05, []string{"a travelling trader", "a tinker with a cart"}},
+	{"creature", 0.35, 0.15, nil},
+}
+
+var creatures = map[string][]string{
+	"forest": {"a red fox", "a wild boar"},
+	"plains": {"a hare", "a grazing deer"},
+	"hill":   {"a mountain goat", "a hawk"},
+	"swamp":  {"a heron", "a giant toad"},
+}
+
+// neighbours are the 8 cells an NPC can step to, like players
+var neighbours = [][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+// npc is how an NPC is stored in its region's Redis hash
+type npc struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (n npc) proto() *pb.NPC {
+	return &pb.NPC{Id: n.ID, Kind: n.Kind, Name: n.Name}
+}
+
+func npcsKey(x, y int) string {
+	// Redis hash of NPCs in a region, by ID
+	// Example: "region:2,4:npcs" -> {"npc-2-4-0": {"kind":"trader",...}}
+	return fmt.Sprintf("region:%d,%d:npcs", x, y)
+}
+
+func npcsSeededKey(x, y int) string {
+	// Set once a region's native NPCs were placed, so they aren't
+	// placed again after they wander off
+	return fmt.Sprintf("region:%d,%d:npcs-seeded", x, y)
+}
+
+// generateNPCs picks a region's native NPCs from the world seed
+// Example: (2,4) "forest" -> [npc-2-4-0 "a red fox"], the same every time
+func generateNPCs(x, y int, terrain string) []npc {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("npcs:%d,%d", x, y)))
+	r := newRand(int64(h.Sum64()) ^ worldSeed)
+
+	var list []npc
+	for _, k := range npcKinds {
+		if r.Float32() >= k.chance {
+			continue
+		}
+		names := k.names
+		if k.kind == "creature" {
+			names = creatures[strings.Fields(terrain)[0]]
+		}
+		if len(names) == 0 {
+			continue
+		}
+		list = append(list, npc{
+			ID:   fmt.Sprintf("npc-%d-%d-%d", x, y, len(list)),
+			Kind: k.kind,
+			Name: names[r.Intn(len(names))],
+		})
+	}
+	return list
+}
+
+// ensureNPCs places a region's native NPCs the first time it's visited
+func ensureNPCs(ctx context.Context, x, y int, terrain string) error {
+	first, err := rdb.SetNX(ctx, npcsSeededKey(x, y), 1, 0).Result()
+	if err != nil || !first {
+		return err
+	}
+	for _, n := range generateNPCs(x, y, terrain) {
+		if err := saveNPC(ctx, x, y, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveNPC(ctx context.Context, x, y int, n npc) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return rdb.HSet(ctx, npcsKey(x, y), n.ID, data).Err()
+}
+
+// listNPCs returns who's in a region, sorted by ID
+func listNPCs(ctx context.Context, x, y int) ([]npc, error) {
+	hash, err := rdb.HGetAll(ctx, npcsKey(x, y)).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]npc, 0, len(hash))
+	for id, data := range hash {
+		var n npc
+		if err := json.Unmarshal([]byte(data), &n); err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable NPC", "id", id, "err", err)
+			continue
+		}
+		list = append(list, n)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// tickNPCs gives each NPC a chance to wander to a neighbouring region
+// scale converts the per-npcTick chances to the simulation's tick
+func tickNPCs(ctx context.Context, x, y int, r *rand.Rand, scale float64) {
+	list, err := listNPCs(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Listing NPCs failed", "err", err)
+		return
+	}
+	for _, n := range list {
+		if r.Float64() >= float64(wanderChance(n.Kind))*scale {
+			continue
+		}
+		step := neighbours[r.Intn(len(neighbours))]
+		toX, toY := x+step[0], y+step[1]
+		if err := handoffNPC(ctx, n, x, y, toX, toY); err != nil {
+			// Nobody's running there (regions only exist near players); stay put
+			npcHandoffs.WithLabelValues("error").Inc()
+			slog.DebugContext(ctx, "NPC stayed", "npc", n.ID, "to_x", toX, "to_y", toY, "err", err)
+			continue
+		}
+		// The neighbour has it now; a failure here leaves it in both places
+		if err := rdb.HDel(ctx, npcsKey(x, y), n.ID).Err(); err != nil {
+			slog.ErrorContext(ctx, "Forgetting handed-off NPC failed", "npc", n.ID, "err", err)
+		}
+		npcHandoffs.WithLabelValues("ok").Inc()
+		slog.InfoContext(ctx, "NPC left", "npc", n.ID, "name", n.Name, "to_x", toX, "to_y", toY)
+	}
+}
+
+func wanderChance(kind string) float32 {
+	for _, k := range npcKinds {
+		if k.kind == kind {
+			return k.wander
+		}
+	}
+	return 0
+}
+
+// handoffNPC asks the neighbouring region's pod to take an NPC
+// Example: region-2-4 -> HandoffNPC on "region-3-4:8081"
+func handoffNPC(ctx context.Context, n npc, fromX, fromY, toX, toY int) error {
+	addr, err := cluster.RegionAddr(ctx, rdb, fmt.Sprintf("region-%d-%d", toX, toY))
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.NewClient(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // No TLS for simplicity
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	_, err = pb.NewRegionServiceClient(conn).HandoffNPC(ctx, &pb.Handoff{
+		Npc:  n.proto(),
+		From: &pb.Position{X: int32(fromX), Y: int32(fromY)},
+		To:   &pb.Position{X: int32(toX), Y: int32(toY)},
+	})
+	return err
+}
+
+func (s *regionServer) HandoffNPC(ctx context.Context, h *pb.Handoff) (*pb.HandoffAck, error) {
+	// Take in an NPC arriving from a neighbouring region
+	// Example: "npc-2-4-0" from (2,4) to (3,4) -> stored in "region:3,4:npcs"
+	if h.Npc == nil || h.Npc.Id == "" || h.From == nil || h.To == nil {
+		return nil, status.Error(codes.InvalidArgument, "npc, from and to are required")
+	}
+	dx, dy := h.To.X-h.From.X, h.To.Y-h.From.Y
+	if dx < -1 || dx > 1 || dy < -1 || dy > 1 || (dx == 0 && dy == 0) {
+		return nil, status.Error(codes.InvalidArgument, "NPCs only move to a neighbouring region")
+	}
+	// Only this pod ticks its own cell, so an NPC saved anywhere else would stand still
+	// Example: a stale handoff for (3,4) reaching region-3-5 -> refused
+	if !s.placed || int(h.To.X) != s.x || int(h.To.Y) != s.y {
+		return nil, status.Errorf(codes.FailedPrecondition, "this isn't region %d,%d", h.To.X, h.To.Y)
+	}
+	n := npc{ID: h.Npc.Id, Kind: h.Npc.Kind, Name: h.Npc.Name}
+	if err := saveNPC(ctx, int(h.To.X), int(h.To.Y), n); err != nil {
+		slog.ErrorContext(ctx, "Saving arriving NPC failed", "npc", n.ID, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	slog.InfoContext(ctx, "NPC arrived", "npc", n.ID, "name", n.Name, "from_x", h.From.X, "from_y", h.From.Y)
+	return &pb.HandoffAck{}, nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/akos011221/driftscape/internal/world"
+)
+
+var (
+	// simTick is how often the region advances its world (SIM_TICK)
+	// Chances below are per npcTick and scaled, so a faster tick isn't a wilder world
+	simTick = 2 * time.Second
+
+	// simIdle is how long the simulation keeps running after the last visit
+	simIdle = 2 * time.Minute
+
+	// occupancyCheck is how often we look for players standing here
+	occupancyCheck = 30 * time.Second
+
+	// lastVisit is when a player last asked this region anything (unix nanos)
+	lastVisit atomic.Int64
+)
+
+// Simulation tuning, as chances per npcTick
+const (
+	fireStartChance  = 0.0005 // A dry region catches fire by itself
+	fireSpreadChance = 0.1    // A burning region sets a neighbour alight
+	fireBurnTicks    = 60     // Ticks a fire burns before going out
+	scorchedFor      = 10 * time.Minute
+	regrowChance     = 0.05 // One missing starting item grows back
+	riverChance      = 0.05 // The river level shifts
+	riverMaxLevel    = 2    // Levels run from -2 (nearly dry) to 2 (in flood)
+)
+
+func stateKey(x, y int) string {
+	// Redis hash of a region's changing state
+	// Example: "region:2,4:state" -> {"fire": "12", "river": "-1", "scorched": "1717000000"}
+	return fmt.Sprintf("region:%d,%d:state", x, y)
+}
+
+// igniteScript sets KEYS[1] alight for ARGV[1] ticks unless it's already
+// burning or still scorched (ARGV[2] is now, in unix seconds)
+var igniteScript = redis.NewScript(`
+if redis.call("HEXISTS", KEYS[1], "fire") == 1 then
+	return 0
+end
+local scorched = tonumber(redis.call("HGET", KEYS[1], "scorched") or "0")
+if scorched > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call("HSET", KEYS[1], "fire", ARGV[1])
+return 1
+`)
+
+// simLeaseScript takes or renews KEYS[1] for holder ARGV[1] for ARGV[2] ms
+// Returns 1 if the caller holds the lease
+var simLeaseScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if holder == false or holder == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// regionState is the part of a region that changes over time
+type regionState struct {
+	fire     int       // Ticks left to burn; 0 means not burning
+	scorched time.Time // Regrowth and new fires wait until then
+	river    int       // -riverMaxLevel..riverMaxLevel
+}
+
+func readState(ctx context.Context, x, y int) (regionState, error) {
+	hash, err := rdb.HGetAll(ctx, stateKey(x, y)).Result()
+	if err != nil {
+		return regionState{}, err
+	}
+	var s regionState
+	s.fire, _ = strconv.Atoi(hash["fire"])
+	s.river, _ = strconv.Atoi(hash["river"])
+	if sec, err := strconv.ParseInt(hash["scorched"], 10, 64); err == nil {
+		s.scorched = time.Unix(sec, 0)
+	}
+	return s, nil
+}
+
+// conditions describes a region's state for players
+// Example: burning forest with a high river -> ["the forest is on fire", "the river runs high"]
+func (s regionState) conditions(terrain string) []string {
+	base := strings.Fields(terrain)[0]
+	var list []string
+	switch {
+	case s.fire > 0:
+		list = append(list, fmt.Sprintf("the %s is on fire", base))
+	case time.Now().Before(s.scorched):
+		list = append(list, fmt.Sprintf("the %s is scorched by a recent fire", base))
+	}
+	switch s.river {
+	case 2:
+		list = append(list, "the river is in flood")
+	case 1:
+		list = append(list, "the river runs high")
+	case -1:
+		list = append(list, "the river runs low")
+	case -2:
+		list = append(list, "the river has nearly run dry")
+	}
+	return list
+}
+
+func flammable(terrain string) bool {
+	// Dry ground burns; wet ground and rivers stop fire
+	// Example: "forest" -> true, "swamp" or "plains with a river" -> false
+	base := strings.Fields(terrain)[0]
+	return (base == "forest" || base == "plains") && !strings.Contains(terrain, "river")
+}
+
+func markVisit() {
+	lastVisit.Store(time.Now().UnixNano())
+}
+
+// runSimulation advances this region every simTick until ctx ends, while
+// a player is here or was recently
+// Example: A visit to region-2-4 keeps its fire spreading for simIdle afterwards
+func runSimulation(ctx context.Context, x, y int) {
+	terrain := world.Terrain(x, y)
+	if err := ensureNPCs(ctx, x, y, terrain); err != nil {
+		slog.ErrorContext(ctx, "Placing NPCs failed", "err", err)
+	}
+	if err := ensureItems(ctx, x, y, terrain); err != nil {
+		slog.ErrorContext(ctx, "Placing items failed", "err", err)
+	}
+
+	// The HPA may run several replicas of this region; only the one holding
+	// the lease simulates, so the world doesn't advance twice per tick
+	holder, _ := os.Hostname()
+	leaseKey := fmt.Sprintf("region:%d,%d:sim-lease", x, y)
+	leaseTTL := 3 * simTick
+
+	r := newRand(time.Now().UnixNano()) // Behaviour needn't repeat
+	scale := float64(simTick) / float64(npcTick)
+	var occupied bool
+	var checkedAt time.Time
+
+	ticker := time.NewTicker(simTick)
+	defer ticker.Stop()
+	slog.InfoContext(ctx, "Simulation running", "tick", simTick)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(checkedAt) > occupancyCheck {
+			occupied, checkedAt = isOccupied(ctx, x, y), time.Now()
+		}
+		if !occupied && time.Since(time.Unix(0, lastVisit.Load())) > simIdle {
+			simTicks.WithLabelValues("idle").Inc()
+			continue
+		}
+		held, err := simLeaseScript.Run(ctx, rdb, []string{leaseKey}, holder, leaseTTL.Milliseconds()).Int()
+		if err != nil || held != 1 {
+			simTicks.WithLabelValues("standby").Inc() // Another replica has it
+			continue
+		}
+
+		start := time.Now()
+		sky := world.Clear
+		if now, err := world.ReadClock(ctx, rdb); err == nil {
+			sky = world.WeatherAt(worldSeed, x, y, now)
+		}
+		tickNPCs(ctx, x, y, r, scale)
+		tickWorld(ctx, x, y, terrain, sky, r, scale)
+		simTickDuration.Observe(time.Since(start).Seconds())
+		simTicks.WithLabelValues("active").Inc()
+	}
+}
+
+// tickWorld advances fire, regrowth and the river by one tick under sky
+func tickWorld(ctx context.Context, x, y int, terrain string, sky world.Weather, r *rand.Rand, scale float64) {
+	s, err := readState(ctx, x, y)
+	if err != nil {
+		slog.WarnContext(ctx, "Reading region state failed", "err", err)
+		return
+	}
+	key := stateKey(x, y)
+	now := time.Now()
+
+	// Fire: burns down, spreads to dry neighbours, leaves the land scorched
+	// Rain puts it out and keeps new ones from starting
+	wet := sky >= world.Rain
+	switch {
+	case s.fire > 1 && wet:
+		rdb.HSet(ctx, key, "fire", 1) // Goes out next tick
+		slog.InfoContext(ctx, "Rain is dousing the fire", "weather", sky)
+	case s.fire > 1:
+		rdb.HIncrBy(ctx, key, "fire", -1)
+		if r.Float64() < fireSpreadChance*scale {
+			step := neighbours[r.Intn(len(neighbours))]
+			nx, ny := x+step[0], y+step[1]
+			if flammable(world.Terrain(nx, ny)) {
+				lit, err := igniteScript.Run(ctx, rdb, []string{stateKey(nx, ny)}, fireBurnTicks, now.Unix()).Int()
+				if err == nil && lit == 1 {
+					slog.InfoContext(ctx, "Fire spread", "to_x", nx, "to_y", ny)
+				}
+			}
+		}
+	case s.fire == 1:
+		rdb.HDel(ctx, key, "fire")
+		rdb.HSet(ctx, key, "scorched", now.Add(scorchedFor).Unix())
+		slog.InfoContext(ctx, "Fire burnt out")
+	case !wet && flammable(terrain) && r.Float64() < fireStartChance*scale:
+		lit, err := igniteScript.Run(ctx, rdb, []string{key}, fireBurnTicks, now.Unix()).Int()
+		if err == nil && lit == 1 {
+			slog.InfoContext(ctx, "Fire started")
+		}
+	}
+
+	// Regrowth: a missing starting item or gathered resource comes back, unless the land is burnt
+	if s.fire == 0 && now.After(s.scorched) && r.Float64() < regrowChance*scale {
+		regrowItem(ctx, x, y, terrain, r)
+	}
+	if s.fire == 0 && now.After(s.scorched) && r.Float64() < resourceRegrowChance*scale {
+		regrowResource(ctx, x, y, terrain, r)
+	}
+
+	// River: drifts up or down, pulled back toward normal; heavy rain fills it
+	if strings.Contains(terrain, "river") && r.Float64() < riverChance*scale {
+		step := 1
+		if sky < world.HeavyRain && r.Intn(2*riverMaxLevel+2) < s.river+riverMaxLevel+1 {
+			step = -1 // The higher (or lower) it is, the likelier it returns
+		}
+		level := max(-riverMaxLevel, min(riverMaxLevel, s.river+step))
+		if level != s.river {
+			rdb.HSet(ctx, key, "river", level)
+		}
+	}
+}
+
+// regrowItem puts back one item the region started with and has lost
+// Example: Started with 3 sticks, 1 left -> 2 sticks
+func regrowItem(ctx context.Context, x, y int, terrain string, r *rand.Rand) {
+	have, err := rdb.HGetAll(ctx, itemsKey(x, y)).Result()
+	if err != nil {
+		slog.WarnContext(ctx, "Reading items failed", "err", err)
+		return
+	}
+	var missing []string
+	for name, start := range generateItems(x, y, terrain) {
+		n, _ := strconv.Atoi(have[name])
+		if n < start {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	name := missing[r.Intn(len(missing))]
+	if err := rdb.HIncrBy(ctx, itemsKey(x, y), name, 1).Err(); err != nil {
+		slog.WarnContext(ctx, "Regrowing item failed", "item", name, "err", err)
+	}
+}
+
+// isOccupied reports whether any player stands on this region
+// Example: "occupants:2,4" -> {"alice"} makes region-2-4 occupied
+func isOccupied(ctx context.Context, x, y int) bool {
+	n, err := rdb.SCard(ctx, occupantsKey(x, y)).Result()
+	if err != nil {
+		slog.WarnContext(ctx, "Checking occupancy failed", "err", err)
+	}
+	return n > 0
+}
+
+func occupantsKey(x, y int) string {
+	// Redis set of players standing in a cell, kept by the Coordinator
+	return fmt.Sprintf("occupants:%d,%d", x, y)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// maxSignText caps what a signpost can say
+const maxSignText = 80
+
+// structureKinds lists what players can build and where
+// A unique kind is stored under its own name, so there's at most one per region
+var structureKinds = []struct {
+	kind   string
+	unique bool
+	limit  int    // Per region, for kinds that aren't unique
+	needs  string // Terrain word required, e.g. bridges need a river
+}{
+	{kind: "camp", unique: true},
+	{kind: "marker", limit: 3},
+	{kind: "bridge", unique: true, needs: "river"},
+	{kind: "signpost", limit: 3},
+	{kind: "road", unique: true}, // Makes the region cheaper to walk into
+}
+
+// structure is how a structure is stored in its region's Redis hash
+type structure struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Builder string `json:"builder"`
+	Text    string `json:"text,omitempty"`
+	BuiltAt int64  `json:"built_at"`
+}
+
+func (s structure) proto() *pb.Structure {
+	return &pb.Structure{Id: s.ID, Kind: s.Kind, Builder: s.Builder, Text: s.Text, BuiltAt: s.BuiltAt}
+}
+
+func structuresKey(x, y int) string {
+	// Redis hash of structures in a region, by ID
+	// Example: "region:2,4:structures" -> {"camp": {...}, "signpost-1": {...}}
+	return fmt.Sprintf("region:%d,%d:structures", x, y)
+}
+
+// listStructures returns what's been built in a region, oldest first
+func listStructures(ctx context.Context, x, y int) ([]structure, error) {
+	hash, err := rdb.HGetAll(ctx, structuresKey(x, y)).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]structure, 0, len(hash))
+	for id, data := range hash {
+		var s structure
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable structure", "id", id, "err", err)
+			continue
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].BuiltAt != list[j].BuiltAt {
+			return list[i].BuiltAt < list[j].BuiltAt
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func hasBridge(list []structure) bool {
+	for _, s := range list {
+		if s.Kind == "bridge" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildScript places structure ARGV[3] (JSON) of kind ARGV[1] in KEYS[1] unless
+// there are already ARGV[2] of it, numbering it from KEYS[2]; returns {1, number}
+// if it was placed, else {0, how many there are}
+var buildScript = redis.NewScript(`
+local n = 0
+for _, id in ipairs(redis.call("HKEYS", KEYS[1])) do
+	if string.sub(id, 1, #ARGV[1] + 1) == ARGV[1] .. "-" then
+		n = n + 1
+	end
+end
+if n >= tonumber(ARGV[2]) then
+	return {0, n}
+end
+local next = redis.call("HINCRBY", KEYS[2], ARGV[1], 1)
+local st = cjson.decode(ARGV[3])
+st.id = ARGV[1] .. "-" .. next
+redis.call("HSET", KEYS[1], st.id, cjson.encode(st))
+return {1, next}
+`)
+
+func (s *regionServer) Build(ctx context.Context, req *pb.BuildRequest) (*pb.Structure, error) {
+	// Place a structure if the region allows it
+	// Example: (2,4) "plains with a river", bridge by alice -> stored as "bridge"
+	if req.Position == nil || req.Builder == "" {
+		return nil, status.Error(codes.InvalidArgument, "position and builder are required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	terrain := world.Terrain(x, y)
+
+	i := -1
+	for j, k := range structureKinds {
+		if k.kind == req.Kind {
+			i = j
+		}
+	}
+	if i < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "can't build a %q", req.Kind)
+	}
+	kind := structureKinds[i]
+	if kind.needs != "" && !strings.Contains(terrain, kind.needs) {
+		return nil, status.Errorf(codes.FailedPrecondition, "a %s needs a %s", kind.kind, kind.needs)
+	}
+
+	text := strings.TrimSpace(req.Text)
+	switch {
+	case kind.kind == "signpost" && text == "":
+		return nil, status.Error(codes.InvalidArgument, "a signpost needs something written on it")
+	case kind.kind != "signpost" && text != "":
+		return nil, status.Errorf(codes.InvalidArgument, "there's nowhere to write on a %s", kind.kind)
+	case len([]rune(text)) > maxSignText || strings.IndexFunc(text, unicode.IsControl) >= 0:
+		return nil, status.Errorf(codes.InvalidArgument, "signposts hold up to %d plain characters", maxSignText)
+	}
+
+	key := structuresKey(x, y)
+	st := structure{ID: kind.kind, Kind: kind.kind, Builder: req.Builder, Text: text, BuiltAt: time.Now().Unix()}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "encoding failed")
+	}
+	if kind.unique {
+		added, err := rdb.HSetNX(ctx, key, st.ID, data).Result()
+		if err != nil {
+			slog.ErrorContext(ctx, "Saving structure failed", "err", err)
+			return nil, status.Error(codes.Unavailable, "region state unavailable")
+		}
+		if !added {
+			return nil, status.Errorf(codes.FailedPrecondition, "there's already a %s here", kind.kind)
+		}
+	} else {
+		// Counted and placed in one step, so builds at once can't pass the limit
+		// Example: two signposts at once with 2 up -> one is "signpost-3", the other is refused
+		res, err := buildScript.Run(ctx, rdb, []string{key, key + "-next"}, kind.kind, kind.limit, data).Int64Slice()
+		if err != nil {
+			slog.ErrorContext(ctx, "Saving structure failed", "err", err)
+			return nil, status.Error(codes.Unavailable, "region state unavailable")
+		}
+		if res[0] == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "there are already %d %ss here", res[1], kind.kind)
+		}
+		st.ID = fmt.Sprintf("%s-%d", kind.kind, res[1])
+	}
+	slog.InfoContext(ctx, "Structure built", "id", st.ID, "builder", req.Builder, "x", x, "y", y)
+	return st.proto(), nil
+}
+
+func (s *regionServer) RemoveStructure(ctx context.Context, req *pb.RemoveRequest) (*pb.Structure, error) {
+	// Take a structure down, e.g. on an admin's request
+	// Example: (2,4) "signpost-1" -> removed and returned
+	if req.Position == nil || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "position and id are required")
+	}
+	x, y := int(req.Position.X), int(req.Position.Y)
+	key := structuresKey(x, y)
+	data, err := rdb.HGet(ctx, key, req.Id).Result()
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "no structure %q here", req.Id)
+	} else if err != nil {
+		slog.ErrorContext(ctx, "Reading structure failed", "id", req.Id, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	removed, err := rdb.HDel(ctx, key, req.Id).Result()
+	if err != nil {
+		slog.ErrorContext(ctx, "Removing structure failed", "id", req.Id, "err", err)
+		return nil, status.Error(codes.Unavailable, "region state unavailable")
+	}
+	if removed == 0 {
+		return nil, status.Errorf(codes.NotFound, "no structure %q here", req.Id) // Someone else got there first
+	}
+	var st structure
+	json.Unmarshal([]byte(data), &st)
+	slog.InfoContext(ctx, "Structure removed", "id", req.Id, "x", x, "y", y)
+	return st.proto(), nil
+}
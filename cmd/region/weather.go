@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/akos011221/driftscape/internal/world"
+	pb "github.com/akos011221/driftscape/proto"
+)
+
+// describeWeather reads the world clock and works out the sky over (x,y)
+// Example: (2,4) "plains with a river", no bridge, at dusk in a storm ->
+// {kind: "storm", summary: "in a raging storm at dusk", blocked: "The river is too swollen..."}
+func describeWeather(ctx context.Context, x, y int, terrain string, bridged bool) *pb.Weather {
+	now, err := world.ReadClock(ctx, rdb)
+	if err != nil {
+		// Without the clock there's no telling the weather; say nothing rather than guess
+		slog.WarnContext(ctx, "Reading world clock failed", "err", err)
+		return nil
+	}
+	w := world.WeatherAt(worldSeed, x, y, now)
+	return &pb.Weather{
+		Kind:      w.String(),
+		TimeOfDay: now.Phase(),
+		Clock:     now.String(),
+		Summary:   world.Describe(w, now),
+		Blocked:   world.Blocked(terrain, w, bridged),
+	}
+}
@@ -0,0 +1,52 @@
+// Package cluster says where DriftScape's pieces run and how they find each
+// other: in Kubernetes by Service DNS, or on one machine as local processes
+// the Coordinator starts itself (ORCHESTRATOR=local).
+//
+// Local regions listen on ports picked at spawn time, so the Coordinator
+// records each one's address in Redis, e.g. "local:regions" {"region-2-4":
+// "127.0.0.1:40123"}, where regions also look up their neighbours.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Domain is the Kubernetes namespace's DNS suffix
+const Domain = "default.svc.cluster.local"
+
+// RegionPort is where a region pod serves gRPC in Kubernetes
+const RegionPort = 8081
+
+// LocalRegionsKey is the Redis hash of local region addresses, by pod name
+const LocalRegionsKey = "local:regions"
+
+// Local reports whether regions run as local processes instead of pods.
+func Local() bool {
+	return os.Getenv("ORCHESTRATOR") == "local"
+}
+
+// RedisAddr is the Redis server to use: REDIS_ADDR, or the in-cluster Service.
+func RedisAddr() string {
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		return addr
+	}
+	return fmt.Sprintf("redis.%s:6379", Domain)
+}
+
+// RegionAddr is where to dial a region's gRPC server.
+// Example: "region-2-4" -> "region-2-4.default.svc.cluster.local:8081",
+// or locally whatever port the Coordinator started it on
+func RegionAddr(ctx context.Context, rdb redis.Cmdable, podName string) (string, error) {
+	if !Local() {
+		return fmt.Sprintf("%s.%s:%d", podName, Domain, RegionPort), nil
+	}
+	addr, err := rdb.HGet(ctx, LocalRegionsKey, podName).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("%s isn't running", podName)
+	}
+	return addr, err
+}
@@ -0,0 +1,785 @@
+// Package coordclient talks to the DriftScape Coordinator's HTTP API.
+//
+// It handles timeouts, retries reads with backoff on transient failures, full
+// body reads and status codes, and returns the Coordinator's replies as
+// structured values so callers never have to guess where the player is.
+package coordclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout covers a move that has to spawn a region pod
+	DefaultTimeout = 20 * time.Second
+	// DefaultRetries is how many times a transient failure is retried
+	DefaultRetries = 3
+	// DefaultBackoff is the wait before the first retry; it doubles each time
+	DefaultBackoff = 200 * time.Millisecond
+
+	maxBodySize = 1 << 20 // Replies are small; refuse anything absurd
+)
+
+// Reply is the Coordinator's answer to a look or move.
+type Reply struct {
+	X       int    `json:"x"` // Where the player is, according to the Coordinator
+	Y       int    `json:"y"`
+	Terrain string `json:"terrain"`
+	Items   []Item `json:"items"` // Lying on the ground here
+	NPCs    []NPC  `json:"npcs"`  // Characters here
+
+	Landmark string `json:"landmark"` // The cell's player-given name, if any
+
+	Players []string  `json:"players"` // Other players here, by name
+	Nearby  []Figures `json:"nearby"`  // Other players in sight, by direction
+
+	Conditions []string `json:"conditions"` // What's happening here, e.g. "the forest is on fire"
+	Weather    *Weather `json:"weather"`    // Nil if the region couldn't tell
+
+	Structures []Structure `json:"structures"` // Built by players, oldest first
+	Resources  []Resource  `json:"resources"`  // What can be gathered here
+
+	Vitals *Vitals `json:"vitals"` // What a move left the player with (move only)
+	Fight  *Fight  `json:"fight"`  // Set if something attacked on arrival (move only)
+
+	// Set inside a cave or ruins: the room's path (e.g. "cave/2") and
+	// its exits, in the order Enter numbers them from 1
+	Sublocation string   `json:"sublocation"`
+	Exits       []string `json:"exits"`
+
+	Message string `json:"message"` // Human-readable, e.g. "You moved to a forest at (0,1)"
+}
+
+// Weather is the sky over a region at the current world time.
+type Weather struct {
+	Kind      string `json:"kind"`        // e.g. "heavy rain"
+	TimeOfDay string `json:"time_of_day"` // e.g. "dusk"
+	Clock     string `json:"clock"`       // e.g. "day 3, 18:40"
+}
+
+// Figures are other players seen at a distance in one direction.
+type Figures struct {
+	Direction string `json:"direction"` // e.g. "north", "southwest"
+	Count     int    `json:"count"`
+}
+
+// Structure is something a player built.
+type Structure struct {
+	ID      string `json:"id"`   // e.g. "camp" or "signpost-3"; unique within its cell
+	Kind    string `json:"kind"` // "camp", "marker", "bridge" or "signpost"
+	Builder string `json:"builder"`
+	Text    string `json:"text"` // What a signpost says
+}
+
+// Resource is a material that can be gathered where the player stands.
+type Resource struct {
+	Name   string `json:"name"`   // e.g. "wood"
+	Amount int    `json:"amount"` // Left to gather; it grows back over time
+	Max    int    `json:"max"`
+}
+
+// Recipe is what it takes to craft an item.
+type Recipe struct {
+	Item  string `json:"item"`
+	Needs []Item `json:"needs"`
+	Makes int    `json:"makes"`
+	Use   string `json:"use"` // e.g. "crosses rivers even in flood"
+}
+
+// CraftReply is the Coordinator's answer to a craft or a recipe list.
+type CraftReply struct {
+	Message   string   `json:"message"`   // e.g. "You make rope from reed (3)."
+	Recipes   []Recipe `json:"recipes"`   // Everything that can be crafted (recipes only)
+	Inventory []Item   `json:"inventory"` // Everything the player now carries (craft only)
+}
+
+// Item is a stack of identical items.
+type Item struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NPC is a non-player character met in a region.
+type NPC struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"` // "wanderer", "trader" or "creature"
+	Name string `json:"name"` // e.g. "a travelling trader"
+}
+
+// ItemReply is the Coordinator's answer to a take, drop or inventory.
+type ItemReply struct {
+	Message   string `json:"message"`   // e.g. "You pick up stick (2)."
+	Inventory []Item `json:"inventory"` // Everything the player now carries
+	Ground    []Item `json:"ground"`    // What's left where the player stands (take and drop only)
+}
+
+// Vitals are how healthy, fresh and fed the player is.
+type Vitals struct {
+	Health  int    `json:"health"`  // Out of 30; at 0 the player wakes up at home
+	Stamina int    `json:"stamina"` // Out of 100; moves cost more in rough terrain
+	Hunger  int    `json:"hunger"`  // Out of 100; 100 is starving
+	Resting bool   `json:"resting"`
+	Feeling string `json:"feeling"` // e.g. "peckish"
+}
+
+// VitalsReply is the Coordinator's answer to a vitals, rest or eat.
+type VitalsReply struct {
+	Message   string  `json:"message"` // e.g. "You eat the fish. Stamina 80/100, ..."
+	Vitals    *Vitals `json:"vitals"`
+	Inventory []Item  `json:"inventory"` // What's left to eat (eat only)
+}
+
+// Quest is a task offered at a cell, with the player's progress once taken on.
+type Quest struct {
+	ID     string `json:"id"`   // Cell and offer it came from, e.g. "2,4#1"
+	Kind   string `json:"kind"` // "visit" or "fetch"
+	Text   string `json:"text"` // e.g. "Find the ancient ruins two cells east"
+	GiverX int    `json:"giver_x"`
+	GiverY int    `json:"giver_y"`
+	X      int    `json:"x"` // Where to go: the sight, or where the items are found
+	Y      int    `json:"y"`
+	Item   string `json:"item"` // Fetch: what to bring back to the giver
+	Count  int    `json:"count"`
+	Have   int    `json:"have"` // Fetch: picked up since accepting
+	Reward []Item `json:"reward"`
+	Done   bool   `json:"done"`
+}
+
+// QuestReply is the Coordinator's answer to quests, journal and their actions.
+type QuestReply struct {
+	Message string  `json:"message"`
+	Quests  []Quest `json:"quests"` // Offered here (quests) or taken on (journal)
+}
+
+// Position is a grid cell, and the room the player is in if they're inside.
+type Position struct {
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Sublocation string `json:"sublocation"` // e.g. "cave/2"; empty outside
+}
+
+// Cell is one discovered cell on a player's map.
+type Cell struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Terrain string `json:"terrain"`
+}
+
+// MapReply lists discovered cells and the player's current position.
+type MapReply struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Cells []Cell `json:"cells"`
+}
+
+// ChatMessage is something another player said, delivered by Events.
+type ChatMessage struct {
+	Scope string    `json:"scope"` // "say", "shout" or "global"
+	From  string    `json:"from"`
+	Text  string    `json:"text"`
+	X     int       `json:"x"` // Where the speaker stood
+	Y     int       `json:"y"`
+	Time  time.Time `json:"time"`
+}
+
+// TravelEvent is progress on a Travel: a cell reached on the way ("step"),
+// arrival ("done") or a trip ended early ("stopped").
+type TravelEvent struct {
+	Kind    string `json:"-"`
+	X       int    `json:"x"` // Where the player now is
+	Y       int    `json:"y"`
+	Terrain string `json:"terrain"` // Of the cell reached (step only)
+	Step    int    `json:"step"`    // 1-based (step only)
+	Steps   int    `json:"steps"`   // Route length (step only)
+	Stamina int    `json:"stamina"` // Left after the step (step only)
+	Message string `json:"message"` // e.g. a quest completed on the way (step only)
+	Reason  string `json:"reason"`  // Why the trip stopped (stopped only)
+	Reply   *Reply `json:"-"`       // The arrival, as a Move reply (done only)
+}
+
+// Landmark is a cell a player has named.
+type Landmark struct {
+	Name  string `json:"name"` // e.g. "The Old Ford"
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Namer string `json:"namer"`
+}
+
+// Gazetteer is a page of landmark search results, best matches first.
+type Gazetteer struct {
+	Landmarks []Landmark `json:"landmarks"`
+	Total     int        `json:"total"` // Matches in all, which may be more than returned
+}
+
+// StatusError is returned when the Coordinator answers with a non-2xx status.
+type StatusError struct {
+	Code    int
+	Message string // Body text, e.g. "Bad x!"
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("coordinator returned %d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("coordinator returned %d: %s", e.Code, e.Message)
+}
+
+// Temporary reports whether retrying the same request may succeed.
+func (e *StatusError) Temporary() bool {
+	switch e.Code {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
+
+// Client is a Coordinator client for one player. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	player  string
+	http    *http.Client
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	admin   string // Sent as X-Admin-Token when set
+}
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client (e.g. one with tracing).
+// It is copied, so the caller's client is never modified.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithTimeout bounds each attempt of a request.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRetries sets how many times transient failures of reads are retried (0 disables).
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithBackoff sets the wait before the first retry.
+func WithBackoff(d time.Duration) Option {
+	return func(c *Client) { c.backoff = d }
+}
+
+// WithAdminToken authorizes admin requests such as RemoveStructure.
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.admin = token }
+}
+
+// New returns a client for player on the Coordinator at baseURL
+// (e.g. "http://localhost:8080").
+func New(baseURL, player string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: baseURL,
+		player:  player,
+		http:    &http.Client{},
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	hc := *c.http // Don't modify a client the caller may share
+	switch {
+	case c.timeout > 0:
+		hc.Timeout = c.timeout
+	case hc.Timeout == 0:
+		hc.Timeout = DefaultTimeout
+	}
+	c.http = &hc
+	return c
+}
+
+// Player returns the player name this client acts for.
+func (c *Client) Player() string { return c.player }
+
+// BaseURL returns the Coordinator address.
+func (c *Client) BaseURL() string { return c.baseURL }
+
+// Position returns the player's last saved position.
+func (c *Client) Position(ctx context.Context) (Position, error) {
+	var p Position
+	err := c.getJSON(ctx, "/position", nil, &p)
+	return p, err
+}
+
+// Look describes the cell at (x, y).
+func (c *Client) Look(ctx context.Context, x, y int) (*Reply, error) {
+	var r Reply
+	if err := c.getJSON(ctx, "/look", xy(x, y), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Move moves the player to (x, y). The returned Reply holds the position
+// the Coordinator actually recorded.
+func (c *Client) Move(ctx context.Context, x, y int) (*Reply, error) {
+	var r Reply
+	if err := c.getJSON(ctx, "/move", xy(x, y), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Enter goes into the cave or ruins where the player stands, or, when
+// already inside, through the exit named or numbered by to.
+func (c *Client) Enter(ctx context.Context, to string) (*Reply, error) {
+	q := url.Values{}
+	if to != "" {
+		q.Set("to", to)
+	}
+	var r Reply
+	if err := c.getJSON(ctx, "/enter", q, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Exit goes back one room, or outside from the entrance.
+func (c *Client) Exit(ctx context.Context) (*Reply, error) {
+	var r Reply
+	if err := c.getJSON(ctx, "/exit", nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Map returns discovered cells inside the given bounding box.
+func (c *Client) Map(ctx context.Context, minX, minY, maxX, maxY int) (*MapReply, error) {
+	q := url.Values{}
+	q.Set("minx", strconv.Itoa(minX))
+	q.Set("miny", strconv.Itoa(minY))
+	q.Set("maxx", strconv.Itoa(maxX))
+	q.Set("maxy", strconv.Itoa(maxY))
+	var m MapReply
+	if err := c.getJSON(ctx, "/map", q, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Take picks up count of the named item where the player stands.
+func (c *Client) Take(ctx context.Context, item string, count int) (*ItemReply, error) {
+	return c.itemRequest(ctx, "/take", url.Values{"item": {item}, "count": {strconv.Itoa(count)}})
+}
+
+// Drop puts down count of the named item where the player stands.
+func (c *Client) Drop(ctx context.Context, item string, count int) (*ItemReply, error) {
+	return c.itemRequest(ctx, "/drop", url.Values{"item": {item}, "count": {strconv.Itoa(count)}})
+}
+
+// Inventory lists what the player carries.
+func (c *Client) Inventory(ctx context.Context) (*ItemReply, error) {
+	return c.itemRequest(ctx, "/inventory", nil)
+}
+
+// Gather gathers one of the named resource where the player stands, or of
+// whatever is most plentiful if resource is empty.
+func (c *Client) Gather(ctx context.Context, resource string) (*ItemReply, error) {
+	var query url.Values
+	if resource != "" {
+		query = url.Values{"resource": {resource}}
+	}
+	return c.itemRequest(ctx, "/gather", query)
+}
+
+// Recipes lists what can be crafted and from what.
+func (c *Client) Recipes(ctx context.Context) (*CraftReply, error) {
+	return c.craftRequest(ctx, "/recipes", nil)
+}
+
+// Craft makes the named item from materials in the inventory.
+func (c *Client) Craft(ctx context.Context, item string) (*CraftReply, error) {
+	return c.craftRequest(ctx, "/craft", url.Values{"item": {item}})
+}
+
+func (c *Client) craftRequest(ctx context.Context, path string, query url.Values) (*CraftReply, error) {
+	var r CraftReply
+	if err := c.getJSON(ctx, path, query, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (c *Client) itemRequest(ctx context.Context, path string, query url.Values) (*ItemReply, error) {
+	var r ItemReply
+	if err := c.getJSON(ctx, path, query, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Vitals reports the player's stamina and hunger.
+func (c *Client) Vitals(ctx context.Context) (*VitalsReply, error) {
+	return c.vitalsRequest(ctx, "/vitals", nil)
+}
+
+// Rest makes stamina come back faster until the player next moves.
+func (c *Client) Rest(ctx context.Context) (*VitalsReply, error) {
+	return c.vitalsRequest(ctx, "/rest", nil)
+}
+
+// Eat eats one of the named item from the inventory.
+func (c *Client) Eat(ctx context.Context, item string) (*VitalsReply, error) {
+	return c.vitalsRequest(ctx, "/eat", url.Values{"item": {item}})
+}
+
+func (c *Client) vitalsRequest(ctx context.Context, path string, query url.Values) (*VitalsReply, error) {
+	var r VitalsReply
+	if err := c.getJSON(ctx, path, query, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Fight is a creature fighting the player.
+type Fight struct {
+	Foe          string `json:"foe"` // e.g. "a grey wolf"
+	FoeHealth    int    `json:"foe_health"`
+	FoeMaxHealth int    `json:"foe_max_health"`
+	Outcome      string `json:"outcome"` // "" while it goes on, then "won", "fled", "scared off" or "defeated"
+}
+
+// FightReply is the Coordinator's answer to a turn of a fight.
+type FightReply struct {
+	X         int     `json:"x"` // Where the player is; home after a defeat
+	Y         int     `json:"y"`
+	Fight     *Fight  `json:"fight"`
+	Vitals    *Vitals `json:"vitals"`
+	Inventory []Item  `json:"inventory"` // What's left after using an item
+	Message   string  `json:"message"`   // e.g. "You hit the grey wolf for 5. The grey wolf strikes you for 3."
+}
+
+// Fight takes a turn in the player's fight: action is "attack", "flee"
+// or "use", with the item to use.
+func (c *Client) Fight(ctx context.Context, action, item string) (*FightReply, error) {
+	query := url.Values{"action": {action}}
+	if item != "" {
+		query.Set("item", item)
+	}
+	var r FightReply
+	if err := c.getJSON(ctx, "/fight", query, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Quests lists the quests offered where the player stands.
+func (c *Client) Quests(ctx context.Context) (*QuestReply, error) {
+	return c.questRequest(ctx, "/quests", nil)
+}
+
+// AcceptQuest takes on the nth (1-based) quest offered where the player stands.
+func (c *Client) AcceptQuest(ctx context.Context, n int) (*QuestReply, error) {
+	return c.questRequest(ctx, "/quests/accept", url.Values{"n": {strconv.Itoa(n)}})
+}
+
+// AbandonQuest gives up an active quest by ID.
+func (c *Client) AbandonQuest(ctx context.Context, id string) (*QuestReply, error) {
+	return c.questRequest(ctx, "/quests/abandon", url.Values{"id": {id}})
+}
+
+// Journal lists the quests the player took on, active ones first.
+func (c *Client) Journal(ctx context.Context) (*QuestReply, error) {
+	return c.questRequest(ctx, "/journal", nil)
+}
+
+func (c *Client) questRequest(ctx context.Context, path string, query url.Values) (*QuestReply, error) {
+	var r QuestReply
+	if err := c.getJSON(ctx, path, query, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Say speaks to players in the same cell.
+func (c *Client) Say(ctx context.Context, text string) (*Reply, error) {
+	return c.chat(ctx, "/say", text)
+}
+
+// Shout speaks to players within a few cells.
+func (c *Client) Shout(ctx context.Context, text string) (*Reply, error) {
+	return c.chat(ctx, "/shout", text)
+}
+
+// Global speaks to every connected player.
+func (c *Client) Global(ctx context.Context, text string) (*Reply, error) {
+	return c.chat(ctx, "/global", text)
+}
+
+func (c *Client) chat(ctx context.Context, path, text string) (*Reply, error) {
+	var r Reply
+	if err := c.getJSON(ctx, path, url.Values{"text": {text}}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Events streams chat meant for the player to handle until ctx ends or the
+// Coordinator closes the stream, which it does when shutting down. Callers
+// reconnect to keep listening. It is not retried and has no timeout.
+func (c *Client) Events(ctx context.Context, handle func(ChatMessage)) error {
+	return c.stream(ctx, "/events", url.Values{}, func(event, data string) {
+		if event != "chat" {
+			return
+		}
+		var m ChatMessage
+		if err := json.Unmarshal([]byte(data), &m); err == nil {
+			handle(m)
+		}
+	})
+}
+
+// Travel walks the player to (x, y) along the Coordinator's route, calling
+// handle for each step and once more when the trip ends. It returns when the
+// trip is over; cancelling ctx leaves the player wherever they've got to.
+// A StatusError means the trip never started, e.g. there's no route.
+func (c *Client) Travel(ctx context.Context, x, y int, handle func(TravelEvent)) error {
+	return c.travel(ctx, xy(x, y), handle)
+}
+
+// TravelTo is Travel to a landmark, by name.
+func (c *Client) TravelTo(ctx context.Context, landmark string, handle func(TravelEvent)) error {
+	return c.travel(ctx, url.Values{"landmark": {landmark}}, handle)
+}
+
+func (c *Client) travel(ctx context.Context, query url.Values, handle func(TravelEvent)) error {
+	return c.stream(ctx, "/travel", query, func(event, data string) {
+		e := TravelEvent{Kind: event}
+		var err error
+		switch event {
+		case "step", "stopped":
+			err = json.Unmarshal([]byte(data), &e)
+		case "done":
+			e.Reply = &Reply{}
+			if err = json.Unmarshal([]byte(data), e.Reply); err == nil {
+				e.X, e.Y = e.Reply.X, e.Reply.Y
+			}
+		default:
+			return
+		}
+		if err == nil {
+			handle(e)
+		}
+	})
+}
+
+// CancelTravel stops the player's trip at its next step, wherever the trip
+// was started from.
+func (c *Client) CancelTravel(ctx context.Context) error {
+	_, err := c.Get(ctx, "/travel/cancel", nil)
+	return err
+}
+
+// stream GETs a Server-Sent Events path and calls handle for each event
+// until the Coordinator ends the stream or ctx ends
+func (c *Client) stream(ctx context.Context, path string, query url.Values, handle func(event, data string)) error {
+	query.Set("player", c.player)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	hc := *c.http
+	hc.Timeout = 0 // The stream stays open as long as we listen
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		return &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	// Server-Sent Events: "event:" and "data:" lines, a blank line ends each one;
+	// lines starting with ":" are keepalives
+	scanner := bufio.NewScanner(resp.Body)
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event != "" {
+				handle(event, data)
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Build places a structure where the player stands; text is what a
+// signpost says and must be empty for anything else.
+func (c *Client) Build(ctx context.Context, kind, text string) (*Reply, error) {
+	q := url.Values{"kind": {kind}}
+	if text != "" {
+		q.Set("text", text)
+	}
+	var r Reply
+	if err := c.getJSON(ctx, "/build", q, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// RemoveStructure takes down a structure at (x, y). It needs WithAdminToken.
+func (c *Client) RemoveStructure(ctx context.Context, x, y int, id string) (*Reply, error) {
+	q := xy(x, y)
+	q.Set("id", id)
+	var r Reply
+	if err := c.getJSON(ctx, "/admin/remove", q, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Name names the cell the player stands in. Names are unique and each cell
+// takes only one; a 409 StatusError means either is taken.
+func (c *Client) Name(ctx context.Context, name string) (*Reply, error) {
+	var r Reply
+	if err := c.getJSON(ctx, "/name", url.Values{"name": {name}}, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Gazetteer searches landmarks by name; an empty query lists them all.
+func (c *Client) Gazetteer(ctx context.Context, query string) (*Gazetteer, error) {
+	var g Gazetteer
+	if err := c.getJSON(ctx, "/gazetteer", url.Values{"q": {query}}, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func xy(x, y int) url.Values {
+	q := url.Values{}
+	q.Set("x", strconv.Itoa(x))
+	q.Set("y", strconv.Itoa(y))
+	return q
+}
+
+// getJSON GETs path with the player added to query and decodes the JSON body into out.
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	body, err := c.Get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding %s reply: %w", path, err)
+	}
+	return nil
+}
+
+// readOnly are the paths that change nothing, so retrying them is safe
+// Anything else may have been done before its reply was lost, e.g. a
+// retried /move would spend stamina twice
+var readOnly = map[string]bool{
+	"/look":      true,
+	"/position":  true,
+	"/map":       true,
+	"/inventory": true,
+	"/vitals":    true,
+	"/journal":   true,
+	"/quests":    true,
+	"/recipes":   true,
+	"/gazetteer": true,
+}
+
+// Get performs a GET on path (with the player added to query) and returns the
+// full body of a 2xx reply. Reads are retried on transient failures with
+// backoff; actions are tried once, since they may have happened anyway.
+func (c *Client) Get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	// A copy, so the caller's values are left as they were
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("player", c.player)
+	target := c.baseURL + path + "?" + q.Encode()
+
+	wait := c.backoff
+	for attempt := 0; ; attempt++ {
+		body, err := c.do(ctx, target)
+		if err == nil || !readOnly[path] || attempt >= c.retries || !retryable(ctx, err) {
+			return body, err
+		}
+
+		// Full jitter keeps many retrying clients from stampeding together
+		sleep := time.Duration(rand.Int63n(int64(wait) + 1))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sleep):
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) do(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.admin != "" {
+		req.Header.Set("X-Admin-Token", c.admin)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("reading reply: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
+
+// retryable reports whether err is worth another attempt
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false // Caller gave up
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Temporary()
+	}
+	return true // Network errors and timeouts
+}
@@ -0,0 +1,74 @@
+package coordclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		status   int // Every reply until the last attempt
+		wantHits int32
+		wantErr  bool
+	}{
+		{name: "read retried on 5xx", path: "/look", status: 503, wantHits: 3},
+		{name: "read not retried on 4xx", path: "/look", status: 400, wantHits: 1, wantErr: true},
+		{name: "action never retried", path: "/move", status: 503, wantHits: 1, wantErr: true},
+		{name: "craft never retried", path: "/craft", status: 502, wantHits: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Fails twice, then answers: enough for a retried read to get through
+				if n := hits.Add(1); n < 3 {
+					http.Error(w, "not now", tt.status)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, "alice", WithRetries(2), WithBackoff(time.Millisecond))
+			_, err := c.Get(context.Background(), tt.path, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get(%s) error = %v, want error %v", tt.path, err, tt.wantErr)
+			}
+			var se *StatusError
+			if err != nil && (!errors.As(err, &se) || se.Code != tt.status) {
+				t.Errorf("Get(%s) error = %v, want a %d StatusError", tt.path, err, tt.status)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("Get(%s) made %d requests, want %d", tt.path, got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestGetLeavesQueryAlone(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	query := url.Values{"x": {"2"}}
+	if _, err := New(srv.URL, "alice").Get(context.Background(), "/look", query); err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("player") != "alice" || got.Get("x") != "2" {
+		t.Errorf("server got %v, want player=alice and x=2", got)
+	}
+	if len(query) != 1 || query.Has("player") {
+		t.Errorf("caller's query changed to %v", query)
+	}
+}
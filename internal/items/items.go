@@ -0,0 +1,122 @@
+// Package items holds what the Coordinator and Region pods share about
+// items: how stacks are stored in Redis hashes and how they read to a player.
+//
+// Both a region's ground and a player's inventory are Redis hashes of
+// item name -> count, e.g. "region:2,4:items" {"stick": 2, "old coin": 1}.
+package items
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxCount caps how many of an item move in one take or drop
+const MaxCount = 99
+
+// namePattern keeps item names short, lowercase words
+var namePattern = regexp.MustCompile(`^[a-z]+( [a-z]+)*$`)
+
+// ValidName reports whether name could be an item
+// Example: "old coin" -> true, "Coin!" -> false
+func ValidName(name string) bool {
+	return len(name) <= 32 && namePattern.MatchString(name)
+}
+
+// Stack is a count of one item
+type Stack struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// takeScript removes ARGV[2] of item ARGV[1] from hash KEYS[1], all or nothing
+// Returns what's left of the item, or -1 if there wasn't enough
+var takeScript = redis.NewScript(`
+local have = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or "0")
+local want = tonumber(ARGV[2])
+if have < want then
+	return -1
+end
+if have == want then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+else
+	redis.call("HINCRBY", KEYS[1], ARGV[1], -want)
+end
+return have - want
+`)
+
+// Take atomically removes count of name from the hash at key
+// ok is false (and nothing changes) if the hash holds fewer than count
+func Take(ctx context.Context, rdb redis.Scripter, key, name string, count int) (ok bool, err error) {
+	left, err := takeScript.Run(ctx, rdb, []string{key}, name, count).Int()
+	if err != nil {
+		return false, err
+	}
+	return left >= 0, nil
+}
+
+// exchangeScript swaps stacks in hash KEYS[1], all or nothing: it gives
+// ARGV[2] of item ARGV[1] for the (name, count) pairs in the rest of ARGV
+// Returns 1 if it did, 0 if the hash didn't hold enough of something
+var exchangeScript = redis.NewScript(`
+for i = 3, #ARGV, 2 do
+	if tonumber(redis.call("HGET", KEYS[1], ARGV[i]) or "0") < tonumber(ARGV[i + 1]) then
+		return 0
+	end
+end
+for i = 3, #ARGV, 2 do
+	if redis.call("HINCRBY", KEYS[1], ARGV[i], -tonumber(ARGV[i + 1])) <= 0 then
+		redis.call("HDEL", KEYS[1], ARGV[i])
+	end
+end
+redis.call("HINCRBY", KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// Exchange atomically takes all of pay from the hash at key and adds get
+// ok is false (and nothing changes) if the hash is short of anything in pay
+// Example: pay [reed 3] for [rope 1] -> {"reed": 4} becomes {"reed": 1, "rope": 1}
+func Exchange(ctx context.Context, rdb redis.Scripter, key string, pay []Stack, get Stack) (ok bool, err error) {
+	args := []any{get.Name, get.Count}
+	for _, s := range pay {
+		args = append(args, s.Name, s.Count)
+	}
+	done, err := exchangeScript.Run(ctx, rdb, []string{key}, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	return done == 1, nil
+}
+
+// Stacks turns an HGETALL result into stacks sorted by name
+// Example: {"stick": "2", "flint": "1"} -> [flint 1] [stick 2]
+func Stacks(hash map[string]string) []Stack {
+	list := make([]Stack, 0, len(hash))
+	for name, v := range hash {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			continue // Leftovers from a bad write; not worth failing over
+		}
+		list = append(list, Stack{Name: name, Count: n})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Describe lists stacks for a player to read
+// Example: [flint 1] [stick 2] -> "flint, stick (2)"
+func Describe(list []Stack) string {
+	parts := make([]string, len(list))
+	for i, s := range list {
+		parts[i] = s.Name
+		if s.Count > 1 {
+			parts[i] = fmt.Sprintf("%s (%d)", s.Name, s.Count)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
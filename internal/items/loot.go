@@ -0,0 +1,39 @@
+package items
+
+// LootTable is what can be found where a terrain word appears
+type LootTable struct {
+	Word  string
+	Items []string
+}
+
+// LootTables lists what can be found where, checked in this order
+// A region gets the table of its base terrain and of each feature it has
+// Example: "forest with ancient ruins" -> forest and ruins items
+var LootTables = []LootTable{
+	{"forest", []string{"stick", "mushroom", "pinecone"}},
+	{"plains", []string{"flint", "wildflower"}},
+	{"hill", []string{"stone", "herb"}},
+	{"swamp", []string{"reed", "frog"}},
+	{"river", []string{"fish", "smooth pebble"}},
+	{"cave", []string{"torch", "crystal"}},
+	{"ruins", []string{"old coin", "clay shard", "rusty sword"}},
+}
+
+// Weapons are what players fight with, and the damage each adds to a hit
+var Weapons = map[string]int{
+	"rusty sword": 3,
+	"stone axe":   2,
+	"stick":       1,
+}
+
+// BestWeapon picks the strongest weapon among what a player carries
+// Example: [rusty sword 1] [stick 2] -> "rusty sword"; no weapons -> ""
+func BestWeapon(carried []Stack) string {
+	best := ""
+	for _, s := range carried {
+		if bonus, ok := Weapons[s.Name]; ok && (best == "" || bonus > Weapons[best]) {
+			best = s.Name
+		}
+	}
+	return best
+}
@@ -0,0 +1,62 @@
+// Package telemetry sets up OpenTelemetry tracing shared by every DriftScape binary.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// SetupTracing installs the global tracer provider and W3C propagators.
+// The exporter is picked by OTEL_TRACES_EXPORTER:
+//   - "otlp": OTLP/gRPC, endpoint from OTEL_EXPORTER_OTLP_ENDPOINT (default localhost:4317)
+//   - "stdout": pretty-printed spans on stdout, handy for tests
+//   - unset or "none": spans are propagated but not exported
+//
+// The returned function flushes pending spans and must be called on exit.
+func SetupTracing(ctx context.Context, service string) (func(context.Context) error, error) {
+	// Propagate trace context even when not exporting, so downstream
+	// services can still join the trace
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "otlp":
+		exporter, err = otlptracegrpc.New(ctx, otlptracegrpc.WithInsecure())
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", os.Getenv("OTEL_TRACES_EXPORTER"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(service),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,85 @@
+// Package world holds what the Coordinator and Region pods share about the
+// world as a whole: the world clock and the weather moving across the grid.
+//
+// The leading Coordinator advances the clock in Redis ("world:clock", in
+// world minutes); regions read it to describe the time of day and weather.
+package world
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ClockKey is the Redis key holding world minutes since the world began
+	ClockKey = "world:clock"
+
+	// MinutesPerDay is the length of a world day in world minutes
+	MinutesPerDay = 24 * 60
+
+	// StartTime is when a new world's clock starts: day 1, early morning
+	StartTime Time = 6 * 60
+)
+
+// Time is a moment in the world, in world minutes since it began
+type Time int64
+
+// Day counts from 1
+func (t Time) Day() int { return int(t/MinutesPerDay) + 1 }
+
+// Hour and Minute are the time of day on a 24-hour clock
+func (t Time) Hour() int   { return int(t%MinutesPerDay) / 60 }
+func (t Time) Minute() int { return int(t % 60) }
+
+// String is how players read the clock
+// Example: 2*MinutesPerDay + 18*60 + 40 -> "day 3, 18:40"
+func (t Time) String() string {
+	return fmt.Sprintf("day %d, %02d:%02d", t.Day(), t.Hour(), t.Minute())
+}
+
+// Phase names the part of the day
+// Example: 05:30 -> "dawn", 19:00 -> "dusk"
+func (t Time) Phase() string {
+	switch h := t.Hour(); {
+	case h < 5 || h >= 21:
+		return "night"
+	case h < 7:
+		return "dawn"
+	case h < 11:
+		return "morning"
+	case h < 14:
+		return "midday"
+	case h < 18:
+		return "afternoon"
+	default:
+		return "dusk"
+	}
+}
+
+// ReadClock returns the current world time
+// Example: "world:clock" -> "2560" is day 2, 18:40; missing -> StartTime
+func ReadClock(ctx context.Context, rdb redis.Cmdable) (Time, error) {
+	s, err := rdb.Get(ctx, ClockKey).Result()
+	if err == redis.Nil {
+		return StartTime, nil
+	} else if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad world clock %q: %w", s, err)
+	}
+	return Time(n), nil
+}
+
+// AdvanceClock moves the world clock on by minutes, starting it if needed
+func AdvanceClock(ctx context.Context, rdb redis.Cmdable, minutes int64) (Time, error) {
+	if err := rdb.SetNX(ctx, ClockKey, int64(StartTime), 0).Err(); err != nil {
+		return 0, err
+	}
+	n, err := rdb.IncrBy(ctx, ClockKey, minutes).Result()
+	return Time(n), err
+}
@@ -0,0 +1,85 @@
+package world
+
+import "container/heap"
+
+// Point is a grid cell
+type Point struct{ X, Y int }
+
+// steps are the 8 neighbouring cells a player can move to
+var steps = []Point{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+// FindPath finds the cheapest route from one cell to another with A*,
+// where cost says what entering a cell costs and false means it can't be
+// entered; costs below 1 break the heuristic, so the route may not be the
+// cheapest. It gives up after exploring limit cells.
+// The route excludes from and ends at to; ok is false if there's none.
+// Example: (0,0) to (2,0) over plains -> [(1,0) (2,0)]
+func FindPath(from, to Point, cost func(Point) (int, bool), limit int) (route []Point, ok bool) {
+	if from == to {
+		return nil, true
+	}
+	// Each move costs at least 1 and diagonals are one move, so the
+	// Chebyshev distance never overestimates
+	h := func(p Point) int { return max(abs(to.X-p.X), abs(to.Y-p.Y)) }
+
+	open := &pointQueue{{p: from, f: h(from)}}
+	came := map[Point]Point{}
+	spent := map[Point]int{from: 0}
+	for explored := 0; open.Len() > 0 && explored < limit; explored++ {
+		cur := heap.Pop(open).(queued)
+		if cur.p == to {
+			for p := to; p != from; p = came[p] {
+				route = append(route, p)
+			}
+			for i, j := 0, len(route)-1; i < j; i, j = i+1, j-1 {
+				route[i], route[j] = route[j], route[i]
+			}
+			return route, true
+		}
+		if cur.g > spent[cur.p] {
+			continue // A cheaper way here was already expanded
+		}
+		for _, s := range steps {
+			next := Point{cur.p.X + s.X, cur.p.Y + s.Y}
+			c, passable := cost(next)
+			if !passable {
+				continue
+			}
+			g := cur.g + c
+			if old, seen := spent[next]; seen && old <= g {
+				continue
+			}
+			spent[next], came[next] = g, cur.p
+			heap.Push(open, queued{p: next, g: g, f: g + h(next)})
+		}
+	}
+	return nil, false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// queued is a cell waiting to be explored, with its cost so far (g) and
+// estimated total (f)
+type queued struct {
+	p    Point
+	g, f int
+}
+
+// pointQueue is a min-heap of cells by estimated total cost
+type pointQueue []queued
+
+func (q pointQueue) Len() int           { return len(q) }
+func (q pointQueue) Less(i, j int) bool { return q[i].f < q[j].f }
+func (q pointQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pointQueue) Push(x any)        { *q = append(*q, x.(queued)) }
+func (q *pointQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
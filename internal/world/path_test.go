@@ -0,0 +1,175 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+)
+
+// grid builds a cost function from rows of cells, top row highest y
+// '.' costs 1, a digit costs that much and '#' or off the grid can't be entered
+func grid(rows ...string) func(Point) (int, bool) {
+	return func(p Point) (int, bool) {
+		row := len(rows) - 1 - p.Y
+		if row < 0 || row >= len(rows) || p.X < 0 || p.X >= len(rows[row]) {
+			return 0, false
+		}
+		switch c := rows[row][p.X]; {
+		case c == '#':
+			return 0, false
+		case c >= '1' && c <= '9':
+			return int(c - '0'), true
+		}
+		return 1, true
+	}
+}
+
+// plains costs 1 everywhere
+func plains(Point) (int, bool) { return 1, true }
+
+func pathCost(route []Point, cost func(Point) (int, bool)) int {
+	total := 0
+	for _, p := range route {
+		c, _ := cost(p)
+		total += c
+	}
+	return total
+}
+
+func TestFindPath(t *testing.T) {
+	walled := grid(
+		"..#..",
+		"..#..",
+		"..#..",
+		".....",
+	)
+	tests := []struct {
+		name     string
+		from, to Point
+		cost     func(Point) (int, bool)
+		limit    int
+		want     []Point // Checked exactly when set
+		wantCost int     // Checked when want isn't set, as ties can go either way
+		wantOK   bool
+	}{
+		{
+			name: "already there", from: Point{2, 2}, to: Point{2, 2},
+			cost: plains, limit: 10, want: nil, wantOK: true,
+		},
+		{
+			name: "straight line", from: Point{0, 0}, to: Point{2, 0},
+			cost: grid("..."), limit: 100, want: []Point{{1, 0}, {2, 0}}, wantOK: true,
+		},
+		{
+			name: "diagonal costs one move", from: Point{0, 0}, to: Point{3, 3},
+			cost: plains, limit: 100, want: []Point{{1, 1}, {2, 2}, {3, 3}}, wantOK: true,
+		},
+		{
+			name: "around a wall", from: Point{0, 3}, to: Point{4, 3},
+			cost: walled, limit: 1000, wantCost: 6, wantOK: true,
+		},
+		{
+			name: "around costly ground", from: Point{0, 0}, to: Point{2, 0},
+			cost: grid(
+				"...",
+				".9.",
+			), limit: 100, want: []Point{{1, 1}, {2, 0}}, wantOK: true,
+		},
+		{
+			name: "through costly ground when it's cheaper", from: Point{0, 1}, to: Point{2, 1},
+			cost: grid(
+				"###",
+				".2.",
+				"###",
+			), limit: 100, want: []Point{{1, 1}, {2, 1}}, wantOK: true,
+		},
+		{
+			name: "blocked destination", from: Point{0, 0}, to: Point{2, 0},
+			cost: grid("..#"), limit: 100, wantOK: false,
+		},
+		{
+			name: "walled in", from: Point{1, 1}, to: Point{5, 5},
+			cost: grid(
+				"###",
+				"#.#",
+				"###",
+			), limit: 100, wantOK: false,
+		},
+		{
+			name: "gives up past the limit", from: Point{0, 0}, to: Point{50, 0},
+			cost: plains, limit: 10, wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, ok := FindPath(tt.from, tt.to, tt.cost, tt.limit)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v (route %v)", ok, tt.wantOK, route)
+			}
+			if !ok {
+				return
+			}
+			if tt.want != nil || tt.from == tt.to {
+				if !reflect.DeepEqual(route, tt.want) {
+					t.Errorf("route = %v, want %v", route, tt.want)
+				}
+				return
+			}
+			if got := pathCost(route, tt.cost); got != tt.wantCost {
+				t.Errorf("route %v costs %d, want %d", route, got, tt.wantCost)
+			}
+			if last := route[len(route)-1]; last != tt.to {
+				t.Errorf("route ends at %v, want %v", last, tt.to)
+			}
+		})
+	}
+}
+
+// The Chebyshev heuristic only holds while every cell costs at least 1;
+// this checks A* still finds the cheapest route against a plain search
+func TestFindPathCheapest(t *testing.T) {
+	cost := grid(
+		"1193",
+		"9#91",
+		"1915",
+		"1119",
+	)
+	from, to := Point{0, 3}, Point{3, 0}
+	route, ok := FindPath(from, to, cost, 1000)
+	if !ok {
+		t.Fatal("no route found")
+	}
+	if got, want := pathCost(route, cost), cheapest(from, to, cost, 4); got != want {
+		t.Errorf("route %v costs %d, want %d", route, got, want)
+	}
+}
+
+// cheapest is Bellman-Ford over a size x size grid, to check FindPath against
+func cheapest(from, to Point, cost func(Point) (int, bool), size int) int {
+	const inf = 1 << 30
+	dist := map[Point]int{from: 0}
+	for changed := true; changed; {
+		changed = false
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				p := Point{x, y}
+				c, ok := cost(p)
+				if !ok || p == from {
+					continue
+				}
+				best, seen := dist[p]
+				if !seen {
+					best = inf
+				}
+				for _, s := range steps {
+					if d, ok := dist[Point{x - s.X, y - s.Y}]; ok && d+c < best {
+						best, changed = d+c, true
+					}
+				}
+				if best < inf {
+					dist[p] = best
+				}
+			}
+		}
+	}
+	return dist[to]
+}
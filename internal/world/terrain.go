@@ -0,0 +1,73 @@
+package world
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+)
+
+// Terrain generates the terrain at (x,y); it's the same everywhere it's
+// called, so the Coordinator can plan across cells whose pods aren't running
+// Example: (2,4) -> "plains with a hill", every time
+func Terrain(x, y int) string {
+	base, feature := baseTerrain(x, y)
+
+	// Rivers join up with a river in the cell to the south
+	// Example: If (2,3) has a river, (2,4)'s river flows south into it
+	if strings.Contains(feature, "river") {
+		if _, south := baseTerrain(x, y-1); strings.Contains(south, "river") {
+			feature = " with a river flowing south"
+		}
+	}
+	// Combine for richer description
+	// Example: "plains with a river flowing south"
+	return base + feature
+}
+
+// baseTerrain is a cell's terrain before neighbours are taken into account
+func baseTerrain(x, y int) (base, feature string) {
+	// Seed randomness with x,y for consistency
+	// Example: (2,4) always gets same base terrain
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%d,%d", x, y)))
+	r := rand.New(rand.NewSource(int64(h.Sum32())))
+
+	bases := []string{"forest", "plains", "hill", "swamp"}
+	base = bases[r.Intn(len(bases))]
+	if r.Float32() < 0.3 { // 30% chance of a feature
+		features := []string{"with a river", "with a cave", "with ancient ruins", "with a hill"}
+		feature = " " + features[r.Intn(len(features))]
+	}
+	return base, feature
+}
+
+// baseCosts is how many moves' worth of effort it takes to enter each terrain
+var baseCosts = map[string]int{
+	"plains": 1,
+	"forest": 2,
+	"hill":   3,
+	"swamp":  3,
+}
+
+// MoveCost is the effort of entering terrain; crossing a river or climbing
+// a hill feature costs one more
+// Example: "plains" -> 1, "swamp with a river" -> 4
+func MoveCost(terrain string) int {
+	cost := 2 // Anything unrecognised is middling
+	if fields := strings.Fields(terrain); len(fields) > 0 {
+		if c, ok := baseCosts[fields[0]]; ok {
+			cost = c
+		}
+	}
+	if strings.Contains(terrain, "river") || strings.Contains(terrain, "with a hill") {
+		cost++
+	}
+	return cost
+}
+
+// RoadCost is MoveCost along a road: half, rounded up
+// Example: "swamp" 3 -> 2, "plains" 1 -> 1
+func RoadCost(terrain string) int {
+	return (MoveCost(terrain) + 1) / 2
+}
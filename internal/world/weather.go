@@ -0,0 +1,151 @@
+package world
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Weather is the sky over one cell, from calmest to wildest
+type Weather int
+
+const (
+	Clear Weather = iota
+	Cloudy
+	Fog
+	Rain
+	HeavyRain
+	Storm
+)
+
+var weatherNames = []string{"clear", "cloudy", "fog", "rain", "heavy rain", "storm"}
+
+// String names the weather
+// Example: HeavyRain -> "heavy rain"
+func (w Weather) String() string {
+	if w < 0 || int(w) >= len(weatherNames) {
+		return "unknown"
+	}
+	return weatherNames[w]
+}
+
+// front is a band of bad weather sweeping across the grid in a straight
+// line, repeating every spacing cells
+type front struct {
+	dirX, dirY float64 // Unit vector it travels along
+	speed      float64 // Cells per world hour
+	spacing    float64 // Cells between one band and the next
+	width      float64 // Cells from a band's centre to its edge
+	strength   float64 // How wet it is at the centre
+	offset     float64 // Where the bands sit at time 0
+}
+
+// frontCount is how many fronts overlap; where they cross, storms brew
+const frontCount = 4
+
+// fronts derives the world's weather fronts from its seed
+// Example: seed 1 always gets the same four fronts
+func fronts(seed int64) []front {
+	h := fnv.New64a()
+	h.Write([]byte("weather"))
+	r := rand.New(rand.NewSource(int64(h.Sum64()) ^ seed))
+
+	list := make([]front, frontCount)
+	for i := range list {
+		angle := r.Float64() * 2 * math.Pi
+		f := front{
+			dirX:     math.Cos(angle),
+			dirY:     math.Sin(angle),
+			speed:    0.5 + r.Float64()*1.5,
+			spacing:  20 + r.Float64()*30,
+			width:    3 + r.Float64()*5,
+			strength: 0.4 + r.Float64()*0.6,
+		}
+		f.offset = r.Float64() * f.spacing
+		list[i] = f
+	}
+	return list
+}
+
+// wetness adds up the fronts passing over (x,y) at t
+// 0 is a cloudless sky; where two strong fronts overlap it can pass 1.5
+func wetness(seed int64, x, y int, t Time) float64 {
+	hours := float64(t) / 60
+	total := 0.0
+	for _, f := range fronts(seed) {
+		// Distance along the front's path, then to the nearest band's centre
+		d := float64(x)*f.dirX + float64(y)*f.dirY - f.speed*hours + f.offset
+		d = math.Mod(d, f.spacing)
+		if d < 0 {
+			d += f.spacing
+		}
+		fromCentre := math.Abs(d - f.spacing/2)
+		total += f.strength * math.Max(0, 1-fromCentre/f.width)
+	}
+	return total
+}
+
+// WeatherAt is the weather over (x,y) at t, the same on every pod
+// Example: seed 1, (2,4), day 3 18:40 -> HeavyRain as a front passes over
+func WeatherAt(seed int64, x, y int, t Time) Weather {
+	w := wetness(seed, x, y, t)
+	switch {
+	case w >= 1.5:
+		return Storm
+	case w >= 1.1:
+		return HeavyRain
+	case w >= 0.7:
+		return Rain
+	case w >= 0.3:
+		if p := t.Phase(); p == "dawn" || p == "night" {
+			return Fog // Damp air settles when it's cool
+		}
+		return Cloudy
+	default:
+		return Clear
+	}
+}
+
+// Describe phrases the weather and time of day for a region's description
+// Example: HeavyRain at dusk -> "under heavy rain at dusk"
+func Describe(w Weather, t Time) string {
+	sky := map[Weather]string{
+		Clear:     "under clear skies",
+		Cloudy:    "under grey skies",
+		Fog:       "in thick fog",
+		Rain:      "in the rain",
+		HeavyRain: "under heavy rain",
+		Storm:     "in a raging storm",
+	}[w]
+	if w == Clear && t.Phase() == "night" {
+		sky = "under the stars"
+	}
+	switch p := t.Phase(); p {
+	case "night", "dawn", "midday", "dusk":
+		return fmt.Sprintf("%s at %s", sky, p)
+	default:
+		return fmt.Sprintf("%s in the %s", sky, p)
+	}
+}
+
+// Blocked says why players can't enter terrain in this weather, or "" if they can
+// A bridge keeps a river crossable whatever the weather
+// Example: "plains with a river" in HeavyRain, no bridge -> "The river is too swollen to cross..."
+func Blocked(terrain string, w Weather, bridged bool) string {
+	return BlockedPast(terrain, w, func(hazard string) bool { return bridged && hazard == "river" })
+}
+
+// BlockedPast is Blocked for a traveller who can get past some hazards,
+// "river" or "hill", whatever the weather, say with a boat or climbing gear
+// Example: "hill with a river" in a Storm, past "river" only -> "It's too dangerous to climb..."
+func BlockedPast(terrain string, w Weather, past func(hazard string) bool) string {
+	if w >= HeavyRain && strings.Contains(terrain, "river") && !past("river") {
+		return fmt.Sprintf("The river is too swollen to cross in the %s; wait for it to pass", w)
+	}
+	if w == Storm && strings.Contains(terrain, "hill") && !past("hill") {
+		return "It's too dangerous to climb the hill in this storm; wait for it to pass"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlockedPast(t *testing.T) {
+	none := func(string) bool { return false }
+	only := func(h string) func(string) bool { return func(hazard string) bool { return hazard == h } }
+	tests := []struct {
+		name    string
+		terrain string
+		weather Weather
+		past    func(string) bool
+		want    string // Start of the reason, "" if not blocked
+	}{
+		{"river in rain", "plains with a river", Rain, none, ""},
+		{"river in heavy rain", "plains with a river", HeavyRain, none, "The river is too swollen"},
+		{"river in a storm", "forest with a river", Storm, none, "The river is too swollen"},
+		{"river with a boat", "plains with a river", Storm, only("river"), ""},
+		{"hill in heavy rain", "hill", HeavyRain, none, ""},
+		{"hill in a storm", "hill", Storm, none, "It's too dangerous to climb"},
+		{"hill with climbing gear", "hill", Storm, only("hill"), ""},
+		{"hill and river, past the river", "hill with a river", Storm, only("river"), "It's too dangerous to climb"},
+		{"hill and river, past the hill", "hill with a river", Storm, only("hill"), "The river is too swollen"},
+		{"plains in a storm", "plains", Storm, none, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BlockedPast(tt.terrain, tt.weather, tt.past)
+			if tt.want == "" && got != "" || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("BlockedPast(%q, %s) = %q, want it to start with %q", tt.terrain, tt.weather, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockedBridge(t *testing.T) {
+	if got := Blocked("plains with a river", Storm, true); got != "" {
+		t.Errorf("bridged river in a storm = %q, want it crossable", got)
+	}
+	if got := Blocked("hill with a river", Storm, true); !strings.HasPrefix(got, "It's too dangerous") {
+		t.Errorf("bridged river on a hill in a storm = %q, want the hill to block", got)
+	}
+}
+
+func TestWeatherAt(t *testing.T) {
+	// The same everywhere it's asked, as every pod works it out alone
+	for _, p := range []Point{{0, 0}, {7, -3}, {-40, 12}} {
+		for _, at := range []Time{0, 555, 3 * MinutesPerDay} {
+			a, b := WeatherAt(42, p.X, p.Y, at), WeatherAt(42, p.X, p.Y, at)
+			if a != b {
+				t.Errorf("WeatherAt(42, %v, %d) gave %s then %s", p, at, a, b)
+			}
+			if a < Clear || a > Storm {
+				t.Errorf("WeatherAt(42, %v, %d) = %d, not a known weather", p, at, a)
+			}
+		}
+	}
+
+	// Fronts move, so a region's weather changes over a few days
+	seen := map[Weather]bool{}
+	for at := Time(0); at < 5*MinutesPerDay; at += 30 {
+		seen[WeatherAt(1, 2, 4, at)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("weather at (2,4) never changed over five days: %v", seen)
+	}
+
+	// Fog only replaces cloud when it's cool
+	for at := Time(0); at < 5*MinutesPerDay; at += 30 {
+		if w := WeatherAt(1, 2, 4, at); w == Fog && at.Phase() != "dawn" && at.Phase() != "night" {
+			t.Errorf("fog at %s, in the %s", at, at.Phase())
+		}
+	}
+}
@@ -19,7 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RegionService_GetDescription_FullMethodName = "/driftscape.RegionService/GetDescription"
+	RegionService_GetDescription_FullMethodName  = "/driftscape.RegionService/GetDescription"
+	RegionService_TakeItem_FullMethodName        = "/driftscape.RegionService/TakeItem"
+	RegionService_DropItem_FullMethodName        = "/driftscape.RegionService/DropItem"
+	RegionService_GetRoom_FullMethodName         = "/driftscape.RegionService/GetRoom"
+	RegionService_HandoffNPC_FullMethodName      = "/driftscape.RegionService/HandoffNPC"
+	RegionService_Build_FullMethodName           = "/driftscape.RegionService/Build"
+	RegionService_RemoveStructure_FullMethodName = "/driftscape.RegionService/RemoveStructure"
+	RegionService_Encounter_FullMethodName       = "/driftscape.RegionService/Encounter"
+	RegionService_FightTurn_FullMethodName       = "/driftscape.RegionService/FightTurn"
+	RegionService_Gather_FullMethodName          = "/driftscape.RegionService/Gather"
 )
 
 // RegionServiceClient is the client API for RegionService service.
@@ -30,6 +39,25 @@ const (
 type RegionServiceClient interface {
 	// Fetches a region's details
 	GetDescription(ctx context.Context, in *Position, opts ...grpc.CallOption) (*Description, error)
+	// Picks up items lying in the region; NOT_FOUND if there aren't enough
+	TakeItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Items, error)
+	// Puts items down in the region
+	DropItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Items, error)
+	// Describes a room inside the region's cave or ruins; NOT_FOUND if there's none
+	GetRoom(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*Room, error)
+	// Hands an NPC over from a neighbouring region, which forgets it on success
+	HandoffNPC(ctx context.Context, in *Handoff, opts ...grpc.CallOption) (*HandoffAck, error)
+	// Places a player-built structure; FAILED_PRECONDITION if it can't go here
+	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*Structure, error)
+	// Takes a structure down; NOT_FOUND if there's no such structure
+	RemoveStructure(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*Structure, error)
+	// Rolls for a creature attacking a player who just arrived; an empty foe means none
+	Encounter(ctx context.Context, in *EncounterRequest, opts ...grpc.CallOption) (*Fight, error)
+	// Plays one turn of a player's fight; NOT_FOUND if they aren't fighting here,
+	// FAILED_PRECONDITION if the turn was already played
+	FightTurn(ctx context.Context, in *FightRequest, opts ...grpc.CallOption) (*Fight, error)
+	// Gathers one of a resource growing in the region; NOT_FOUND if there's none left
+	Gather(ctx context.Context, in *GatherRequest, opts ...grpc.CallOption) (*Gathered, error)
 }
 
 type regionServiceClient struct {
@@ -50,6 +78,96 @@ func (c *regionServiceClient) GetDescription(ctx context.Context, in *Position,
 	return out, nil
 }
 
+func (c *regionServiceClient) TakeItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Items, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Items)
+	err := c.cc.Invoke(ctx, RegionService_TakeItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) DropItem(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*Items, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Items)
+	err := c.cc.Invoke(ctx, RegionService_DropItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) GetRoom(ctx context.Context, in *RoomRequest, opts ...grpc.CallOption) (*Room, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Room)
+	err := c.cc.Invoke(ctx, RegionService_GetRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) HandoffNPC(ctx context.Context, in *Handoff, opts ...grpc.CallOption) (*HandoffAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandoffAck)
+	err := c.cc.Invoke(ctx, RegionService_HandoffNPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*Structure, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Structure)
+	err := c.cc.Invoke(ctx, RegionService_Build_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) RemoveStructure(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*Structure, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Structure)
+	err := c.cc.Invoke(ctx, RegionService_RemoveStructure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) Encounter(ctx context.Context, in *EncounterRequest, opts ...grpc.CallOption) (*Fight, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fight)
+	err := c.cc.Invoke(ctx, RegionService_Encounter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) FightTurn(ctx context.Context, in *FightRequest, opts ...grpc.CallOption) (*Fight, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fight)
+	err := c.cc.Invoke(ctx, RegionService_FightTurn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionServiceClient) Gather(ctx context.Context, in *GatherRequest, opts ...grpc.CallOption) (*Gathered, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Gathered)
+	err := c.cc.Invoke(ctx, RegionService_Gather_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RegionServiceServer is the server API for RegionService service.
 // All implementations must embed UnimplementedRegionServiceServer
 // for forward compatibility.
@@ -58,6 +176,25 @@ func (c *regionServiceClient) GetDescription(ctx context.Context, in *Position,
 type RegionServiceServer interface {
 	// Fetches a region's details
 	GetDescription(context.Context, *Position) (*Description, error)
+	// Picks up items lying in the region; NOT_FOUND if there aren't enough
+	TakeItem(context.Context, *ItemRequest) (*Items, error)
+	// Puts items down in the region
+	DropItem(context.Context, *ItemRequest) (*Items, error)
+	// Describes a room inside the region's cave or ruins; NOT_FOUND if there's none
+	GetRoom(context.Context, *RoomRequest) (*Room, error)
+	// Hands an NPC over from a neighbouring region, which forgets it on success
+	HandoffNPC(context.Context, *Handoff) (*HandoffAck, error)
+	// Places a player-built structure; FAILED_PRECONDITION if it can't go here
+	Build(context.Context, *BuildRequest) (*Structure, error)
+	// Takes a structure down; NOT_FOUND if there's no such structure
+	RemoveStructure(context.Context, *RemoveRequest) (*Structure, error)
+	// Rolls for a creature attacking a player who just arrived; an empty foe means none
+	Encounter(context.Context, *EncounterRequest) (*Fight, error)
+	// Plays one turn of a player's fight; NOT_FOUND if they aren't fighting here,
+	// FAILED_PRECONDITION if the turn was already played
+	FightTurn(context.Context, *FightRequest) (*Fight, error)
+	// Gathers one of a resource growing in the region; NOT_FOUND if there's none left
+	Gather(context.Context, *GatherRequest) (*Gathered, error)
 	mustEmbedUnimplementedRegionServiceServer()
 }
 
@@ -71,6 +208,33 @@ type UnimplementedRegionServiceServer struct{}
 func (UnimplementedRegionServiceServer) GetDescription(context.Context, *Position) (*Description, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDescription not implemented")
 }
+func (UnimplementedRegionServiceServer) TakeItem(context.Context, *ItemRequest) (*Items, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeItem not implemented")
+}
+func (UnimplementedRegionServiceServer) DropItem(context.Context, *ItemRequest) (*Items, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropItem not implemented")
+}
+func (UnimplementedRegionServiceServer) GetRoom(context.Context, *RoomRequest) (*Room, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoom not implemented")
+}
+func (UnimplementedRegionServiceServer) HandoffNPC(context.Context, *Handoff) (*HandoffAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandoffNPC not implemented")
+}
+func (UnimplementedRegionServiceServer) Build(context.Context, *BuildRequest) (*Structure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Build not implemented")
+}
+func (UnimplementedRegionServiceServer) RemoveStructure(context.Context, *RemoveRequest) (*Structure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveStructure not implemented")
+}
+func (UnimplementedRegionServiceServer) Encounter(context.Context, *EncounterRequest) (*Fight, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encounter not implemented")
+}
+func (UnimplementedRegionServiceServer) FightTurn(context.Context, *FightRequest) (*Fight, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FightTurn not implemented")
+}
+func (UnimplementedRegionServiceServer) Gather(context.Context, *GatherRequest) (*Gathered, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Gather not implemented")
+}
 func (UnimplementedRegionServiceServer) mustEmbedUnimplementedRegionServiceServer() {}
 func (UnimplementedRegionServiceServer) testEmbeddedByValue()                       {}
 
@@ -110,6 +274,168 @@ func _RegionService_GetDescription_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RegionService_TakeItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).TakeItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_TakeItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).TakeItem(ctx, req.(*ItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_DropItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).DropItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_DropItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).DropItem(ctx, req.(*ItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_GetRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).GetRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_GetRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).GetRoom(ctx, req.(*RoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_HandoffNPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Handoff)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).HandoffNPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_HandoffNPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).HandoffNPC(ctx, req.(*Handoff))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).Build(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_Build_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).Build(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_RemoveStructure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).RemoveStructure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_RemoveStructure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).RemoveStructure(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_Encounter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncounterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).Encounter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_Encounter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).Encounter(ctx, req.(*EncounterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_FightTurn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).FightTurn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_FightTurn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).FightTurn(ctx, req.(*FightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegionService_Gather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServiceServer).Gather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegionService_Gather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServiceServer).Gather(ctx, req.(*GatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RegionService_ServiceDesc is the grpc.ServiceDesc for RegionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,6 +447,42 @@ var RegionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDescription",
 			Handler:    _RegionService_GetDescription_Handler,
 		},
+		{
+			MethodName: "TakeItem",
+			Handler:    _RegionService_TakeItem_Handler,
+		},
+		{
+			MethodName: "DropItem",
+			Handler:    _RegionService_DropItem_Handler,
+		},
+		{
+			MethodName: "GetRoom",
+			Handler:    _RegionService_GetRoom_Handler,
+		},
+		{
+			MethodName: "HandoffNPC",
+			Handler:    _RegionService_HandoffNPC_Handler,
+		},
+		{
+			MethodName: "Build",
+			Handler:    _RegionService_Build_Handler,
+		},
+		{
+			MethodName: "RemoveStructure",
+			Handler:    _RegionService_RemoveStructure_Handler,
+		},
+		{
+			MethodName: "Encounter",
+			Handler:    _RegionService_Encounter_Handler,
+		},
+		{
+			MethodName: "FightTurn",
+			Handler:    _RegionService_FightTurn_Handler,
+		},
+		{
+			MethodName: "Gather",
+			Handler:    _RegionService_Gather_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/driftscape.proto",